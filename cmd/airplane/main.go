@@ -30,6 +30,17 @@ func main() {
 			return
 		}
 
+		exitCode := 1
+		var ecerr utils.ExitCodeError
+		if errors.As(err, &ecerr) {
+			exitCode = ecerr.Code
+			if ecerr.Err == nil {
+				analytics.Close()
+				os.Exit(exitCode)
+			}
+			err = ecerr.Err
+		}
+
 		logger.Debug("Error: %+v", err)
 		logger.Log("")
 		if exerr, ok := errors.Cause(err).(utils.ErrorExplained); ok {
@@ -44,7 +55,7 @@ func main() {
 		analytics.ReportError(err)
 
 		analytics.Close()
-		os.Exit(1)
+		os.Exit(exitCode)
 	}
 }
 
@@ -16,6 +16,7 @@ import (
 
 	"github.com/airplanedev/cli/pkg/logger"
 	"github.com/airplanedev/cli/pkg/version"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/pkg/errors"
 )
@@ -72,6 +73,62 @@ type Client struct {
 	// Alternative to token-based authn.
 	APIKey string
 	TeamID string
+
+	// Reauthenticate is called when the token has expired or is
+	// rejected by the API, and returns a new token to retry with.
+	//
+	// If nil, or if it returns an error, the request fails instead.
+	Reauthenticate func(ctx context.Context, expired string) (string, error)
+
+	// LoadToken is called before a request when Token is empty, and
+	// returns the token to use, or an empty string if there is none.
+	//
+	// If nil, or if it returns an error, the request uses the API key.
+	LoadToken func(ctx context.Context) (string, error)
+}
+
+// TokenExpiry returns when the token expires, it returns false if the
+// token is not set or does not expire.
+//
+// The token is not verified, this is only meant to avoid sending expired tokens.
+func (c Client) TokenExpiry() (time.Time, bool) {
+	if c.Token == "" {
+		return time.Time{}, false
+	}
+	var claims jwt.RegisteredClaims
+	if _, _, err := new(jwt.Parser).ParseUnverified(c.Token, &claims); err != nil {
+		logger.Debug("error parsing token: %v", err)
+		return time.Time{}, false
+	}
+	if claims.ExpiresAt == nil {
+		return time.Time{}, false
+	}
+	return claims.ExpiresAt.Time, true
+}
+
+// Scope identifies the API host and team that the client acts on, such as
+// "api.airplane.dev/tea1234", to keep data of different teams and environments apart.
+//
+// The token is not verified, so the scope must not be used for authorization.
+func (c Client) Scope() string {
+	team := c.TeamID
+	if team == "" && c.Token != "" {
+		var claims struct {
+			jwt.RegisteredClaims
+			TeamID string `json:"teamID"`
+		}
+		if _, _, err := new(jwt.Parser).ParseUnverified(c.Token, &claims); err != nil {
+			logger.Debug("error parsing token: %v", err)
+		}
+		team = claims.TeamID
+	}
+	return c.host() + "/" + team
+}
+
+// TokenExpired returns true if the token has expired.
+func (c Client) TokenExpired() bool {
+	exp, ok := c.TokenExpiry()
+	return ok && !time.Now().Before(exp)
 }
 
 // AppURL returns the app URL.
@@ -214,12 +271,27 @@ func (c Client) RunTask(ctx context.Context, req RunTaskRequest) (res RunTaskRes
 }
 
 // Watcher runs a task with the given arguments and returns a run watcher.
+//
+// The run is cancelled when the context is canceled.
 func (c Client) Watcher(ctx context.Context, req RunTaskRequest) (*Watcher, error) {
 	resp, err := c.RunTask(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	return newWatcher(ctx, c, resp.RunID), nil
+	return newWatcher(ctx, c, resp.RunID, true), nil
+}
+
+// WatchRun returns a run watcher for an existing run.
+//
+// Unlike Watcher, canceling the context stops watching without cancelling the run.
+func (c Client) WatchRun(ctx context.Context, runID string) *Watcher {
+	return newWatcher(ctx, c, runID, false)
+}
+
+// CancelRun cancels a run by id.
+func (c Client) CancelRun(ctx context.Context, id string) (err error) {
+	err = c.do(ctx, "POST", "/runs/cancel", CancelRunRequest{RunID: id}, nil)
+	return
 }
 
 // GetRun returns a run by id.
@@ -268,6 +340,16 @@ func (c Client) GetTask(ctx context.Context, slug string) (res Task, err error)
 	return
 }
 
+// GetTaskByID returns a task by its ID.
+func (c Client) GetTaskByID(ctx context.Context, id string) (res Task, err error) {
+	q := url.Values{"taskID": []string{id}}
+	if err = c.do(ctx, "GET", "/tasks/getTaskByID?"+q.Encode(), nil, &res); err != nil {
+		return
+	}
+	res.URL = c.TaskURL(res.Slug)
+	return
+}
+
 // GetConfig returns a config by name and tag.
 func (c Client) GetConfig(ctx context.Context, req GetConfigRequest) (res GetConfigResponse, err error) {
 	err = c.do(ctx, "POST", "/configs/get", req, &res)
@@ -280,6 +362,19 @@ func (c Client) SetConfig(ctx context.Context, req SetConfigRequest) (err error)
 	return
 }
 
+// ListConfigs lists config variables.
+func (c Client) ListConfigs(ctx context.Context, req ListConfigsRequest) (res ListConfigsResponse, err error) {
+	q := url.Values{"showSecrets": []string{strconv.FormatBool(req.ShowSecrets)}}
+	err = c.do(ctx, "GET", "/configs/list?"+q.Encode(), nil, &res)
+	return
+}
+
+// DeleteConfig deletes a config variable by name and tag.
+func (c Client) DeleteConfig(ctx context.Context, req DeleteConfigRequest) (err error) {
+	err = c.do(ctx, "POST", "/configs/delete", req, nil)
+	return
+}
+
 // GetBuild returns metadata about a hosted build.
 func (c Client) GetBuild(ctx context.Context, id string) (res GetBuildResponse, err error) {
 	q := url.Values{"id": []string{id}}
@@ -299,6 +394,12 @@ func (c Client) CreateBuildUpload(ctx context.Context, req CreateBuildUploadRequ
 	return
 }
 
+// CreateUpload creates an upload for a file parameter and returns metadata about it.
+func (c Client) CreateUpload(ctx context.Context, req CreateUploadRequest) (res CreateUploadResponse, err error) {
+	err = c.do(ctx, "POST", "/uploads/create", req, &res)
+	return
+}
+
 // CreateAPIKey creates a new API key and returns data about it.
 func (c Client) CreateAPIKey(ctx context.Context, req CreateAPIKeyRequest) (res CreateAPIKeyResponse, err error) {
 	err = c.do(ctx, "POST", "/apiKeys/create", req, &res)
@@ -338,15 +439,52 @@ func (c Client) ListResources(ctx context.Context) (res ListResourcesResponse, e
 
 // Do sends a request with `method`, `path`, `payload` and `reply`.
 func (c Client) do(ctx context.Context, method, path string, payload, reply interface{}) error {
-	var url = "https://" + c.host() + "/v0" + path
-	var body io.Reader
-
+	var body []byte
 	if payload != nil {
 		buf, err := json.Marshal(payload)
 		if err != nil {
 			return errors.Wrap(err, "api: marshal payload")
 		}
-		body = bytes.NewReader(buf)
+		body = buf
+	}
+
+	if c.Token == "" && c.LoadToken != nil {
+		token, err := c.LoadToken(ctx)
+		if err != nil && c.APIKey == "" {
+			return err
+		} else if err != nil {
+			logger.Debug("error loading token: %v", err)
+		}
+		c.Token = token
+	}
+
+	var err error
+	if c.TokenExpired() {
+		err = Error{Code: http.StatusUnauthorized, Message: "token expired"}
+	} else {
+		err = c.doOnce(ctx, method, path, body, reply)
+	}
+
+	// Log in again and retry once if the token was rejected.
+	if e, ok := err.(Error); ok && e.Code == http.StatusUnauthorized && c.Token != "" && c.Reauthenticate != nil {
+		token, rerr := c.Reauthenticate(ctx, c.Token)
+		if rerr != nil {
+			logger.Debug("error reauthenticating: %v", rerr)
+			return err
+		}
+		c.Token = token
+		return c.doOnce(ctx, method, path, body, reply)
+	}
+
+	return err
+}
+
+func (c Client) doOnce(ctx context.Context, method, path string, payload []byte, reply interface{}) error {
+	var url = "https://" + c.host() + "/v0" + path
+	var body io.Reader
+
+	if payload != nil {
+		body = bytes.NewReader(payload)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
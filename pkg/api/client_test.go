@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenExpiry(t *testing.T) {
+	newToken := func(t *testing.T, claims jwt.Claims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		var assert = require.New(t)
+		exp := time.Now().Add(-time.Minute).Truncate(time.Second)
+		c := Client{Token: newToken(t, jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(exp)})}
+
+		got, ok := c.TokenExpiry()
+		assert.True(ok)
+		assert.True(exp.Equal(got))
+		assert.True(c.TokenExpired())
+	})
+
+	t.Run("not expired", func(t *testing.T) {
+		var assert = require.New(t)
+		exp := time.Now().Add(time.Hour)
+		c := Client{Token: newToken(t, jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(exp)})}
+
+		assert.False(c.TokenExpired())
+	})
+
+	t.Run("no expiry", func(t *testing.T) {
+		var assert = require.New(t)
+		c := Client{Token: newToken(t, jwt.MapClaims{"userID": "usr"})}
+
+		_, ok := c.TokenExpiry()
+		assert.False(ok)
+		assert.False(c.TokenExpired())
+
+		_, ok = Client{Token: "not a jwt"}.TokenExpiry()
+		assert.False(ok)
+	})
+}
+
+func TestScope(t *testing.T) {
+	var assert = require.New(t)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"teamID": "tea1"}).SignedString([]byte("secret"))
+	assert.NoError(err)
+
+	assert.Equal("api.airplane.dev/tea1", Client{Host: "api.airplane.dev", Token: token}.Scope())
+	assert.Equal("api.airstage.app/tea2", Client{Host: "api.airstage.app", APIKey: "key", TeamID: "tea2"}.Scope())
+}
+
+func TestGetTaskByID(t *testing.T) {
+	var assert = require.New(t)
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0/tasks/getTaskByID" || r.URL.Query().Get("taskID") != "tsk1" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": "task not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"taskID": "tsk1", "slug": "hello"}`)
+	}))
+	defer srv.Close()
+
+	prev := client
+	client = srv.Client()
+	defer func() { client = prev }()
+
+	c := Client{Host: srv.Listener.Addr().String(), APIKey: "key", TeamID: "tea1"}
+	task, err := c.GetTaskByID(context.Background(), "tsk1")
+	assert.NoError(err)
+	assert.Equal("tsk1", task.ID)
+	assert.Equal("hello", task.Slug)
+
+	_, err = c.GetTaskByID(context.Background(), "tsk2")
+	var apiErr Error
+	assert.ErrorAs(err, &apiErr)
+	assert.Equal(http.StatusNotFound, apiErr.Code)
+}
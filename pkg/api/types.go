@@ -64,6 +64,14 @@ type Permission struct {
 
 type Action string
 
+// Actions that can be granted on a task.
+const (
+	ActionGetTask     Action = "tasks.get"
+	ActionRequestRun  Action = "tasks.request_run"
+	ActionExecuteTask Action = "tasks.execute"
+	ActionUpdateTask  Action = "tasks.update"
+)
+
 type UpdateTaskResponse struct {
 	TaskRevisionID string `json:"taskRevisionID"`
 }
@@ -392,6 +400,11 @@ type RunTaskResponse struct {
 	RunID string `json:"runID"`
 }
 
+// CancelRunRequest represents a cancel run request.
+type CancelRunRequest struct {
+	RunID string `json:"runID"`
+}
+
 // GetRunResponse represents a get task response.
 type GetRunResponse struct {
 	Run Run `json:"run"`
@@ -470,6 +483,22 @@ type GetConfigResponse struct {
 	Config Config `json:"config"`
 }
 
+// ListConfigsRequest represents a list configs request.
+type ListConfigsRequest struct {
+	ShowSecrets bool
+}
+
+// ListConfigsResponse represents a list configs response.
+type ListConfigsResponse struct {
+	Configs []Config `json:"configs"`
+}
+
+// DeleteConfigRequest represents a delete config request.
+type DeleteConfigRequest struct {
+	Name string `json:"name"`
+	Tag  string `json:"tag"`
+}
+
 type GetBuildResponse struct {
 	Build Build `json:"build"`
 }
@@ -529,6 +558,16 @@ type CreateBuildUploadResponse struct {
 	WriteOnlyURL string `json:"writeOnlyURL"`
 }
 
+type CreateUploadRequest struct {
+	FileName  string `json:"fileName"`
+	SizeBytes int    `json:"sizeBytes"`
+}
+
+type CreateUploadResponse struct {
+	Upload       Upload `json:"upload"`
+	WriteOnlyURL string `json:"writeOnlyURL"`
+}
+
 type Upload struct {
 	ID  string `json:"id"`
 	URL string `json:"url"`
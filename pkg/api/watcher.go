@@ -13,6 +13,10 @@ var (
 	// fetchInterval is the interval to use for fetching
 	// new run states.
 	fetchInterval = 1 * time.Second
+
+	// cancelTimeout is the maximum time to wait for a run
+	// to be cancelled once the watcher's context is canceled.
+	cancelTimeout = 30 * time.Second
 )
 
 // LogsClient represents a logs client.
@@ -20,6 +24,7 @@ type logsClient interface {
 	GetLogs(ctx context.Context, runID, prevToken string) (GetLogsResponse, error)
 	GetOutputs(ctx context.Context, runID string) (GetOutputsResponse, error)
 	GetRun(ctx context.Context, runID string) (GetRunResponse, error)
+	CancelRun(ctx context.Context, runID string) error
 }
 
 // RunState represents a run state.
@@ -53,19 +58,23 @@ func (r RunState) Failed() bool {
 
 // Watcher represents a run watcher.
 type Watcher struct {
-	ctx    context.Context
-	client logsClient
-	runID  string
-	state  chan RunState
+	ctx          context.Context
+	client       logsClient
+	runID        string
+	state        chan RunState
+	cancelOnDone bool
 }
 
 // NewWatcher returns a new watcher with the given runID and context.
-func newWatcher(ctx context.Context, client logsClient, runID string) *Watcher {
+//
+// When cancelOnDone is true, the run is cancelled once the context is canceled.
+func newWatcher(ctx context.Context, client logsClient, runID string, cancelOnDone bool) *Watcher {
 	w := &Watcher{
-		ctx:    ctx,
-		client: client,
-		runID:  runID,
-		state:  make(chan RunState),
+		ctx:          ctx,
+		client:       client,
+		runID:        runID,
+		state:        make(chan RunState),
+		cancelOnDone: cancelOnDone,
 	}
 	go w.watch()
 	return w
@@ -85,42 +94,79 @@ func (w *Watcher) Next() RunState {
 //
 // On every tick the method attempts to fetch the most recent
 // logs and run status and sends them on an internal "state" channel
-// on fetch failure, or when the task is canceled a special state
+// on fetch failure, or when the context is canceled a special state
 // is sent with an error.
+//
+// When the watcher cancels on done, a canceled context instead sends a
+// cancel request and keeps watching the run until the API reports that it stopped.
 func (w *Watcher) watch() {
 	var ticker = time.NewTicker(fetchInterval)
 	var prev RunState
+	var ctx = w.ctx
+
+	defer ticker.Stop()
 
 	for {
 		select {
-		case <-w.ctx.Done():
-			// TODO(amir): actually send a cancel request
-			// and wait for the API state change.
-			w.send(w.ctx, RunState{})
+		case <-ctx.Done():
+			if ctx != w.ctx {
+				w.state <- RunState{
+					err: errors.Wrap(ctx.Err(), "waiting for run to be cancelled"),
+				}
+				return
+			}
+
+			if !w.cancelOnDone || prev.Stopped() {
+				w.state <- RunState{
+					err: ctx.Err(),
+				}
+				return
+			}
+
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(context.Background(), cancelTimeout)
+			defer cancel()
+
+			if err := w.client.CancelRun(ctx, w.runID); err != nil {
+				w.state <- RunState{
+					err: errors.Wrap(err, "cancel run"),
+				}
+				return
+			}
 
 		case <-ticker.C:
-			state, err := w.fetch(w.ctx, prev)
+			state, err := w.fetch(ctx, prev)
 			if err != nil {
-				w.send(w.ctx, RunState{
+				if ctx.Err() != nil {
+					// The context was canceled mid-fetch, the next
+					// iteration handles it.
+					continue
+				}
+				w.state <- RunState{
 					err: err,
-				})
+				}
 				return
 			}
 
-			w.send(w.ctx, state)
-			prev = state
+			// The state is only considered sent when the receiver got it,
+			// otherwise its logs are fetched again on the next tick.
+			if w.send(ctx, state) {
+				prev = state
+			}
 		}
 	}
 }
 
 // Send sends the given state with context.
-func (w *Watcher) send(ctx context.Context, state RunState) {
+//
+// It returns false if the context was canceled before the
+// state was received.
+func (w *Watcher) send(ctx context.Context, state RunState) bool {
 	select {
 	case w.state <- state:
+		return true
 	case <-ctx.Done():
-		w.state <- RunState{
-			err: ctx.Err(),
-		}
+		return false
 	}
 }
 
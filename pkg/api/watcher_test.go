@@ -84,7 +84,7 @@ func TestWatcher(t *testing.T) {
 		ctx, cancel := context.WithCancel(ctx)
 		defer cancel()
 
-		var w = newWatcher(ctx, lcm, "run_id")
+		var w = newWatcher(ctx, lcm, "run_id", false)
 		var state RunState
 		var printed []string
 
@@ -105,12 +105,67 @@ func TestWatcher(t *testing.T) {
 		assert.NoError(state.Err())
 		assert.Equal([]string{"A", "B"}, printed)
 	})
+
+	t.Run("cancels the run when the context is canceled", func(t *testing.T) {
+		var assert = require.New(t)
+		var lcm = logsClientMock{}
+		var cancelled int64
+
+		lcm.getLogs = func(runID, prevToken string) (GetLogsResponse, error) {
+			return GetLogsResponse{}, nil
+		}
+
+		lcm.getRun = func(string) (GetRunResponse, error) {
+			var run = Run{Status: RunActive}
+
+			if atomic.LoadInt64(&cancelled) > 0 {
+				run.Status = RunCancelled
+			}
+
+			return GetRunResponse{run}, nil
+		}
+
+		lcm.getOutputs = func(string) (GetOutputsResponse, error) {
+			return GetOutputsResponse{}, nil
+		}
+
+		lcm.cancelRun = func(runID string) error {
+			assert.Equal("run_id", runID)
+			atomic.AddInt64(&cancelled, 1)
+			return nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var w = newWatcher(ctx, lcm, "run_id", true)
+		var state RunState
+
+		for {
+			if state = w.Next(); state.Err() != nil {
+				break
+			}
+
+			if state.Status == RunActive {
+				cancel()
+			}
+
+			if state.Stopped() {
+				break
+			}
+		}
+
+		assert.NoError(state.Err())
+		assert.Equal(RunCancelled, state.Status)
+		assert.Equal(int64(1), atomic.LoadInt64(&cancelled))
+	})
 }
 
 type logsClientMock struct {
 	getLogs    func(runID string, s string) (GetLogsResponse, error)
 	getRun     func(runID string) (GetRunResponse, error)
 	getOutputs func(runID string) (GetOutputsResponse, error)
+	cancelRun  func(runID string) error
 }
 
 func (lcm logsClientMock) GetLogs(ctx context.Context, runID, s string) (GetLogsResponse, error) {
@@ -124,3 +179,7 @@ func (lcm logsClientMock) GetRun(ctx context.Context, runID string) (GetRunRespo
 func (lcm logsClientMock) GetOutputs(ctx context.Context, runID string) (GetOutputsResponse, error) {
 	return lcm.getOutputs(runID)
 }
+
+func (lcm logsClientMock) CancelRun(ctx context.Context, runID string) error {
+	return lcm.cancelRun(runID)
+}
@@ -18,6 +18,13 @@ type Request struct {
 	TaskEnv api.TaskEnv
 	Shim    bool
 	GitMeta api.BuildGitMeta
+
+	// NoCache disables reusing the upload or build of a previous
+	// deploy when the contents of Root are unchanged.
+	NoCache bool
+	// ReuseBuild skips building the task when it was already built from
+	// the same contents, kind options and env. Remote builds only.
+	ReuseBuild bool
 }
 
 // Response represents a build response.
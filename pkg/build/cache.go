@@ -0,0 +1,284 @@
+package build
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/logger"
+	libBuild "github.com/airplanedev/lib/pkg/build"
+	"github.com/airplanedev/lib/pkg/build/ignore"
+	"github.com/pkg/errors"
+)
+
+// cacheTTL is how long uploads and builds are reused for.
+//
+// Uploads and builds are not kept forever by the API, so entries
+// expire well before they could be cleaned up.
+const cacheTTL = 24 * time.Hour
+
+// hashTaskDir returns a content hash of the files in root that are
+// included in its build archive.
+//
+// The hash only depends on the paths, modes and contents of the files,
+// so it is the same for two roots with the same files.
+func hashTaskDir(root string) (string, error) {
+	include, err := ignore.Func(root)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	// filepath.Walk visits files in lexical order, which keeps the hash deterministic.
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if ok, err := include(path, info); err != nil {
+			return err
+		} else if !ok {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		// Paths and modes are separated by NUL bytes, which cannot appear in either.
+		if _, err := io.WriteString(h, filepath.ToSlash(rel)+"\x00"+info.Mode().String()+"\x00"); err != nil {
+			return err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(h, target+"\x00")
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		_, err = h.Write([]byte{0})
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "hashing task root")
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// uploadCacheKey returns the key of an upload of a task root in the cache.
+//
+// Uploads are only accessible to the team and API host they were made with,
+// which are identified by scope (see api.Client.Scope).
+func uploadCacheKey(scope, hash string) string {
+	return scope + "/" + hash
+}
+
+// buildCacheKey returns the key of a build of a task in the cache.
+//
+// Builds depend on the kind, options and env of the task, on top of the
+// contents of its root, and are only accessible within scope like uploads.
+// They also depend on the version of the CLI, which generates their shim.
+func buildCacheKey(scope, cliVersion, taskID, hash string, kind libBuild.TaskKind, kindOptions libBuild.KindOptions, env api.TaskEnv) (string, error) {
+	buf, err := json.Marshal(map[string]interface{}{
+		"scope":       scope,
+		"cliVersion":  cliVersion,
+		"taskID":      taskID,
+		"hash":        hash,
+		"kind":        kind,
+		"kindOptions": kindOptions,
+		"env":         env,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling build cache key")
+	}
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// archiveCache is a local cache of the uploads and builds of task roots,
+// keyed by the content hash of the root along with the API host and team,
+// so that deploying an unchanged task does not upload it again.
+//
+// Errors are logged rather than returned, since deploys work without it.
+type archiveCache struct {
+	path string
+	mu   sync.Mutex
+}
+
+type archiveCacheContents struct {
+	Uploads map[string]cachedUpload `json:"uploads,omitempty"`
+	Builds  map[string]cachedBuild  `json:"builds,omitempty"`
+}
+
+type cachedUpload struct {
+	UploadID  string    `json:"uploadID"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type cachedBuild struct {
+	BuildID   string    `json:"buildID"`
+	ImageURL  string    `json:"imageURL"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// newArchiveCache returns a cache stored under ~/.airplane, or nil if the
+// home directory cannot be determined.
+func newArchiveCache() *archiveCache {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		logger.Debug("disabling archive cache: %s", err)
+		return nil
+	}
+	return &archiveCache{
+		path: filepath.Join(home, ".airplane", "cache", "archives.json"),
+	}
+}
+
+// upload returns the ID of a previous upload with the given key, see uploadCacheKey.
+func (c *archiveCache) upload(key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	u, ok := c.read().Uploads[key]
+	if !ok || time.Since(u.CreatedAt) > cacheTTL {
+		return "", false
+	}
+	return u.UploadID, true
+}
+
+// build returns a previous successful build with the given key.
+func (c *archiveCache) build(key string) (cachedBuild, bool) {
+	if c == nil {
+		return cachedBuild{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.read().Builds[key]
+	if !ok || time.Since(b.CreatedAt) > cacheTTL {
+		return cachedBuild{}, false
+	}
+	return b, true
+}
+
+// forgetUpload removes the upload with the given key, after the API has rejected it.
+func (c *archiveCache) forgetUpload(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	contents := c.read()
+	if _, ok := contents.Uploads[key]; !ok {
+		return
+	}
+	delete(contents.Uploads, key)
+	if err := c.write(contents); err != nil {
+		logger.Debug("writing archive cache: %s", err)
+	}
+}
+
+// put records a successful build, along with the upload it was built from.
+func (c *archiveCache) put(uploadKey, uploadID, buildKey string, b cachedBuild) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	contents := c.read()
+	now := time.Now()
+	for k, u := range contents.Uploads {
+		if now.Sub(u.CreatedAt) > cacheTTL {
+			delete(contents.Uploads, k)
+		}
+	}
+	for k, b := range contents.Builds {
+		if now.Sub(b.CreatedAt) > cacheTTL {
+			delete(contents.Builds, k)
+		}
+	}
+
+	if _, ok := contents.Uploads[uploadKey]; !ok {
+		contents.Uploads[uploadKey] = cachedUpload{UploadID: uploadID, CreatedAt: now}
+	}
+	b.CreatedAt = now
+	contents.Builds[buildKey] = b
+
+	if err := c.write(contents); err != nil {
+		logger.Debug("writing archive cache: %s", err)
+	}
+}
+
+func (c *archiveCache) read() archiveCacheContents {
+	contents := archiveCacheContents{}
+	if buf, err := ioutil.ReadFile(c.path); err == nil {
+		if err := json.Unmarshal(buf, &contents); err != nil {
+			logger.Debug("reading archive cache: %s", err)
+		}
+	} else if !os.IsNotExist(err) {
+		logger.Debug("reading archive cache: %s", err)
+	}
+
+	if contents.Uploads == nil {
+		contents.Uploads = map[string]cachedUpload{}
+	}
+	if contents.Builds == nil {
+		contents.Builds = map[string]cachedBuild{}
+	}
+	return contents
+}
+
+func (c *archiveCache) write(contents archiveCacheContents) error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0777); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+
+	buf, err := json.MarshalIndent(contents, "", "	")
+	if err != nil {
+		return errors.Wrap(err, "marshal archive cache")
+	}
+
+	// Write to a temporary file first, so that concurrent deploys never read a partial file.
+	tmp, err := ioutil.TempFile(filepath.Dir(c.path), "archives-*.json")
+	if err != nil {
+		return errors.Wrap(err, "write archive cache")
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "write archive cache")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "write archive cache")
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), c.path), "write archive cache")
+}
@@ -0,0 +1,112 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	libBuild "github.com/airplanedev/lib/pkg/build"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashTaskDir(t *testing.T) {
+	require := require.New(t)
+
+	newRoot := func(files map[string]string) string {
+		root, err := ioutil.TempDir("", "airplane-hash-")
+		require.NoError(err)
+		t.Cleanup(func() { os.RemoveAll(root) })
+		for name, contents := range files {
+			path := filepath.Join(root, name)
+			require.NoError(os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(ioutil.WriteFile(path, []byte(contents), 0644))
+		}
+		return root
+	}
+
+	files := map[string]string{
+		"main.py":          "print('hello')",
+		"lib/helpers.py":   "def hello(): pass",
+		"requirements.txt": "",
+	}
+	a, err := hashTaskDir(newRoot(files))
+	require.NoError(err)
+	b, err := hashTaskDir(newRoot(files))
+	require.NoError(err)
+	require.Equal(a, b)
+
+	files["lib/helpers.py"] = "def hello(): return 1"
+	c, err := hashTaskDir(newRoot(files))
+	require.NoError(err)
+	require.NotEqual(a, c)
+
+	// Moving contents between files changes the hash.
+	d, err := hashTaskDir(newRoot(map[string]string{"ab": "c"}))
+	require.NoError(err)
+	e, err := hashTaskDir(newRoot(map[string]string{"a": "bc"}))
+	require.NoError(err)
+	require.NotEqual(d, e)
+}
+
+func TestArchiveCache(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "airplane-cache-")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	c := &archiveCache{path: filepath.Join(dir, "cache", "archives.json")}
+
+	prod := uploadCacheKey("api.airplane.dev/tea1", "hash")
+	_, ok := c.upload(prod)
+	require.False(ok)
+
+	c.put(prod, "upl1", "key", cachedBuild{BuildID: "bld1", ImageURL: "image:bld1"})
+
+	uploadID, ok := c.upload(prod)
+	require.True(ok)
+	require.Equal("upl1", uploadID)
+
+	// Uploads of other hosts or teams are not reused.
+	_, ok = c.upload(uploadCacheKey("api.airstage.app/tea1", "hash"))
+	require.False(ok)
+	_, ok = c.upload(uploadCacheKey("api.airplane.dev/tea2", "hash"))
+	require.False(ok)
+
+	b, ok := c.build("key")
+	require.True(ok)
+	require.Equal("bld1", b.BuildID)
+	require.Equal("image:bld1", b.ImageURL)
+
+	_, ok = c.build("other")
+	require.False(ok)
+
+	// Uploads that the API rejected are uploaded again.
+	c.forgetUpload(prod)
+	_, ok = c.upload(prod)
+	require.False(ok)
+	_, ok = c.build("key")
+	require.True(ok)
+
+	// A nil cache is disabled.
+	var disabled *archiveCache
+	disabled.put("hash", "upl1", "key", cachedBuild{})
+	_, ok = disabled.upload("hash")
+	require.False(ok)
+}
+
+func TestBuildCacheKey(t *testing.T) {
+	require := require.New(t)
+
+	key := func(cliVersion string, kindOptions libBuild.KindOptions) string {
+		k, err := buildCacheKey("api.airplane.dev/tea1", cliVersion, "tsk1", "hash", libBuild.TaskKindNode, kindOptions, nil)
+		require.NoError(err)
+		return k
+	}
+
+	a := key("0.3.0", libBuild.KindOptions{"entrypoint": "main.ts"})
+	require.Equal(a, key("0.3.0", libBuild.KindOptions{"entrypoint": "main.ts"}))
+	// Builds of another CLI version have another shim.
+	require.NotEqual(a, key("0.3.1", libBuild.KindOptions{"entrypoint": "main.ts"}))
+	require.NotEqual(a, key("0.3.0", libBuild.KindOptions{"entrypoint": "index.ts"}))
+}
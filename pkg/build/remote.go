@@ -17,6 +17,7 @@ import (
 	"github.com/airplanedev/cli/pkg/logger"
 	"github.com/airplanedev/cli/pkg/taskdir/definitions"
 	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/version"
 	libBuild "github.com/airplanedev/lib/pkg/build"
 	"github.com/airplanedev/lib/pkg/build/ignore"
 	"github.com/dustin/go-humanize"
@@ -35,12 +36,17 @@ type Deployer struct {
 	cachedRegistryToken   *api.RegistryTokenResponse
 
 	uploadArchiveSingleFlightGroup singleflight.Group
-	uploadedArchives               map[string]string
+	// uploadedArchives maps the upload cache keys of task roots to their upload.
+	uploadedArchives map[string]archiveUpload
+	uploadedMutex    sync.Mutex
+
+	cache *archiveCache
 }
 
 func NewDeployer() *Deployer {
 	return &Deployer{
-		uploadedArchives: make(map[string]string),
+		uploadedArchives: make(map[string]archiveUpload),
+		cache:            newArchiveCache(),
 	}
 }
 
@@ -53,45 +59,67 @@ func (d *Deployer) remote(ctx context.Context, req Request) (*libBuild.Response,
 	defer loader.Stop()
 	loader.Start()
 
-	// Before performing a remote build, we must first update kind/kindOptions
-	// since the remote build relies on pulling those from the tasks table (for now).
-	if err := updateKindAndOptions(ctx, req.Client, req.Def, req.Shim); err != nil {
+	kind, kindOptions, err := getKindAndOptions(req.Def, req.Shim)
+	if err != nil {
 		return nil, err
 	}
 
-	buildLog(ctx, api.LogLevelInfo, loader, logger.Gray("Authenticating with Airplane..."))
-	registry, err := d.getRegistryToken(ctx, req.Client)
+	hash, err := hashTaskDir(req.Root)
 	if err != nil {
 		return nil, err
 	}
+	logger.Debug("Content hash of %s: %s", req.Root, hash)
 
-	tmpdir, err := ioutil.TempDir("", "airplane-builds-")
+	scope := req.Client.Scope()
+	uploadKey := uploadCacheKey(scope, hash)
+	cacheKey, err := buildCacheKey(scope, version.Get(), req.TaskID, hash, kind, kindOptions, req.TaskEnv)
 	if err != nil {
-		return nil, errors.Wrap(err, "creating temporary directory for remote build")
+		return nil, err
+	}
+	if req.ReuseBuild && !req.NoCache {
+		if b, ok := d.cache.build(cacheKey); ok {
+			buildLog(ctx, api.LogLevelInfo, loader, logger.Gray("Task is unchanged since build %s, reusing it.", b.BuildID))
+			return &libBuild.Response{
+				ImageURL: b.ImageURL,
+				BuildID:  b.BuildID,
+			}, nil
+		}
 	}
-	defer os.RemoveAll(tmpdir)
 
-	archivePath := path.Join(tmpdir, "archive.tar.gz")
-	buildLog(ctx, api.LogLevelInfo, loader, logger.Gray("Packaging and uploading %s to build the task...", req.Root))
-	if err := archiveTaskDir(req.Root, archivePath); err != nil {
+	// Before performing a remote build, we must first update kind/kindOptions
+	// since the remote build relies on pulling those from the tasks table (for now).
+	if err := updateKindAndOptions(ctx, req.Client, req.Def.GetSlug(), kind, kindOptions); err != nil {
 		return nil, err
 	}
 
-	uploadIDRes, err, _ := d.uploadArchiveSingleFlightGroup.Do(req.Root, func() (interface{}, error) {
-		return d.uploadArchive(ctx, req.Client, archivePath, req.Root, loader)
-	})
-
+	buildLog(ctx, api.LogLevelInfo, loader, logger.Gray("Authenticating with Airplane..."))
+	registry, err := d.getRegistryToken(ctx, req.Client)
 	if err != nil {
 		return nil, err
 	}
-	uploadID := uploadIDRes.(string)
 
-	build, err := req.Client.CreateBuild(ctx, api.CreateBuildRequest{
-		TaskID:         req.TaskID,
-		SourceUploadID: uploadID,
-		Env:            req.TaskEnv,
-		GitMeta:        req.GitMeta,
-	})
+	upload, err := d.upload(ctx, req, uploadKey, req.NoCache, loader)
+	if err != nil {
+		return nil, err
+	}
+	createBuild := func() (api.CreateBuildResponse, error) {
+		return req.Client.CreateBuild(ctx, api.CreateBuildRequest{
+			TaskID:         req.TaskID,
+			SourceUploadID: upload.id,
+			Env:            req.TaskEnv,
+			GitMeta:        req.GitMeta,
+		})
+	}
+	build, err := createBuild()
+	if err != nil && upload.cached {
+		// The API may have expired an upload of a previous deploy, upload the root again.
+		logger.Debug("Creating build from cached upload %s failed: %v", upload.id, err)
+		d.forgetUpload(uploadKey)
+		if upload, err = d.upload(ctx, req, uploadKey, true, loader); err != nil {
+			return nil, err
+		}
+		build, err = createBuild()
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "creating build")
 	}
@@ -106,6 +134,10 @@ func (d *Deployer) remote(ctx context.Context, req Request) (*libBuild.Response,
 		libBuild.SanitizeTaskID(req.TaskID),
 		build.Build.ID,
 	)
+	d.cache.put(uploadKey, upload.id, cacheKey, cachedBuild{
+		BuildID:  build.Build.ID,
+		ImageURL: imageURL,
+	})
 
 	return &libBuild.Response{
 		ImageURL: imageURL,
@@ -128,15 +160,11 @@ func (d *Deployer) getRegistryToken(ctx context.Context, client *api.Client) (re
 	return registryToken, nil
 }
 
-func updateKindAndOptions(ctx context.Context, client *api.Client, def definitions.DefinitionInterface, shim bool) error {
-	task, err := client.GetTask(ctx, def.GetSlug())
-	if err != nil {
-		return err
-	}
-
+// getKindAndOptions returns the kind and kind options that the task of def is built with.
+func getKindAndOptions(def definitions.DefinitionInterface, shim bool) (libBuild.TaskKind, libBuild.KindOptions, error) {
 	kind, kindOptions, err := def.GetKindAndOptions()
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 
 	// Conditionally instruct the remote builder API to perform a shim-based build.
@@ -150,6 +178,16 @@ func updateKindAndOptions(ctx context.Context, client *api.Client, def definitio
 		kindOptions["entrypoint"] = filepath.ToSlash(ep)
 	}
 
+	return kind, kindOptions, nil
+}
+
+// updateKindAndOptions updates the kind and kind options of the task with the given slug.
+func updateKindAndOptions(ctx context.Context, client *api.Client, slug string, kind libBuild.TaskKind, kindOptions libBuild.KindOptions) error {
+	task, err := client.GetTask(ctx, slug)
+	if err != nil {
+		return err
+	}
+
 	_, err = client.UpdateTask(ctx, api.UpdateTaskRequest{
 		Kind:        kind,
 		KindOptions: kindOptions,
@@ -172,7 +210,7 @@ func updateKindAndOptions(ctx context.Context, client *api.Client, def definitio
 		Timeout:                    task.Timeout,
 	})
 	if err != nil {
-		return errors.Wrapf(err, "updating task %s", def.GetSlug())
+		return errors.Wrapf(err, "updating task %s", slug)
 	}
 
 	return nil
@@ -206,25 +244,79 @@ func archiveTaskDir(root string, archivePath string) error {
 	return nil
 }
 
-func (d *Deployer) uploadArchive(ctx context.Context, client *api.Client, archivePath, rootPath string, loader logger.Loader) (string, error) {
-	// Check if anyone has uploaded an archive for this path.
-	uid, ok := d.uploadedArchives[rootPath]
+// archiveUpload is an upload of a task root.
+type archiveUpload struct {
+	id string
+	// cached is true if the upload was made by a previous deploy, in which
+	// case the API may have expired it since.
+	cached bool
+}
+
+// upload uploads the root of req once for all the tasks that share it, see uploadArchive.
+func (d *Deployer) upload(ctx context.Context, req Request, key string, noCache bool, loader logger.Loader) (archiveUpload, error) {
+	res, err, _ := d.uploadArchiveSingleFlightGroup.Do(key, func() (interface{}, error) {
+		return d.uploadArchive(ctx, req.Client, req.Root, key, noCache, loader)
+	})
+	if err != nil {
+		return archiveUpload{}, err
+	}
+	return res.(archiveUpload), nil
+}
+
+// forgetUpload forgets the upload with the given key, so that the next
+// upload of its root uploads it again.
+func (d *Deployer) forgetUpload(key string) {
+	d.uploadedMutex.Lock()
+	delete(d.uploadedArchives, key)
+	d.uploadedMutex.Unlock()
+	d.cache.forgetUpload(key)
+}
+
+// uploadArchive archives and uploads the task root, unless a root with
+// the same upload cache key was already uploaded, and returns the upload.
+func (d *Deployer) uploadArchive(ctx context.Context, client *api.Client, rootPath, key string, noCache bool, loader logger.Loader) (archiveUpload, error) {
+	// Check if anyone has uploaded an archive with the same contents.
+	d.uploadedMutex.Lock()
+	u, ok := d.uploadedArchives[key]
+	d.uploadedMutex.Unlock()
 	if ok {
-		// Somebody has already uploaded the path. Re-use the upload ID.
-		return uid, nil
+		// Somebody has already uploaded the contents. Re-use the upload.
+		return u, nil
+	}
+	if !noCache {
+		if uid, ok := d.cache.upload(key); ok {
+			buildLog(ctx, api.LogLevelInfo, loader, logger.Gray("%s is unchanged since the last deploy, skipping upload.", rootPath))
+			u := archiveUpload{id: uid, cached: true}
+			d.uploadedMutex.Lock()
+			d.uploadedArchives[key] = u
+			d.uploadedMutex.Unlock()
+			return u, nil
+		}
+	}
+
+	tmpdir, err := ioutil.TempDir("", "airplane-builds-")
+	if err != nil {
+		return archiveUpload{}, errors.Wrap(err, "creating temporary directory for remote build")
+	}
+	defer os.RemoveAll(tmpdir)
+
+	archivePath := path.Join(tmpdir, "archive.tar.gz")
+	buildLog(ctx, api.LogLevelInfo, loader, logger.Gray("Packaging and uploading %s to build the task...", rootPath))
+	if err := archiveTaskDir(rootPath, archivePath); err != nil {
+		return archiveUpload{}, err
 	}
 
 	loader.Start()
 
 	archive, err := os.OpenFile(archivePath, os.O_RDONLY, 0)
 	if err != nil {
-		return "", errors.Wrap(err, "opening archive file")
+		return archiveUpload{}, errors.Wrap(err, "opening archive file")
 	}
 	defer archive.Close()
 
 	info, err := archive.Stat()
 	if err != nil {
-		return "", errors.Wrap(err, "stat on archive file")
+		return archiveUpload{}, errors.Wrap(err, "stat on archive file")
 	}
 	sizeBytes := int(info.Size())
 
@@ -236,28 +328,30 @@ func (d *Deployer) uploadArchive(ctx context.Context, client *api.Client, archiv
 		SizeBytes: sizeBytes,
 	})
 	if err != nil {
-		return "", errors.Wrap(err, "creating upload")
+		return archiveUpload{}, errors.Wrap(err, "creating upload")
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "PUT", upload.WriteOnlyURL, archive)
 	if err != nil {
-		return "", errors.Wrap(err, "creating GCS upload request")
+		return archiveUpload{}, errors.Wrap(err, "creating GCS upload request")
 	}
 	req.Header.Add("X-Goog-Content-Length-Range", fmt.Sprintf("0,%d", sizeBytes))
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return "", errors.Wrap(err, "uploading to GCS")
+		return archiveUpload{}, errors.Wrap(err, "uploading to GCS")
 	}
 	defer resp.Body.Close()
 
 	logger.Debug("Upload complete: %s", upload.Upload.URL)
-	uploadID := upload.Upload.ID
+	uploaded := archiveUpload{id: upload.Upload.ID}
 
 	// Populate the cache so that we can reuse the upload.
-	d.uploadedArchives[rootPath] = uploadID
+	d.uploadedMutex.Lock()
+	d.uploadedArchives[key] = uploaded
+	d.uploadedMutex.Unlock()
 
-	return uploadID, nil
+	return uploaded, nil
 }
 
 func waitForBuild(ctx context.Context, loader logger.Loader, client *api.Client, buildID string) error {
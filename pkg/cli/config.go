@@ -1,7 +1,10 @@
 package cli
 
 import (
+	"os"
+
 	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/conf"
 	"github.com/airplanedev/cli/pkg/logger"
 	"github.com/golang-jwt/jwt/v4"
 )
@@ -27,6 +30,49 @@ type Config struct {
 
 	// Version indicates if the CLI version should be printed.
 	Version bool
+
+	// Profile is the name of the profile in use, if any.
+	//
+	// It is set from the --profile flag, AIRPLANE_PROFILE or
+	// the default profile, in that order.
+	Profile string
+
+	// Env are the env vars of the profile in use, see Getenv.
+	Env map[string]string
+
+	// OpenCredentials opens the store of the tokens the CLI logs in with.
+	//
+	// It is set in the root command, use CredentialStore to open the store once.
+	OpenCredentials func() (conf.CredentialStore, error)
+
+	credentials conf.CredentialStore
+}
+
+// CredentialStore returns the store of the tokens the CLI logs in with.
+//
+// The store is only opened when it is first used, since opening it may
+// ask for a passphrase or move tokens out of the config.
+func (c *Config) CredentialStore() (conf.CredentialStore, error) {
+	if c.credentials == nil {
+		store, err := c.OpenCredentials()
+		if err != nil {
+			return nil, err
+		}
+		c.credentials = store
+	}
+	return c.credentials, nil
+}
+
+// Getenv returns the value of an env var, or its value in the profile
+// in use if it is not set.
+//
+// Env vars of profiles are not set in the environment of the CLI, so that
+// they do not leak into the processes it runs, e.g. tasks run locally.
+func (c *Config) Getenv(key string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return c.Env[key]
 }
 
 // ParseTokenForAnalytics parses UNVERIFIED JWT information - this information can be spoofed.
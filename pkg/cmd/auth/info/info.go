@@ -2,7 +2,9 @@ package info
 
 import (
 	"context"
+	"time"
 
+	"github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/cli"
 	"github.com/airplanedev/cli/pkg/logger"
 	"github.com/airplanedev/cli/pkg/print"
@@ -20,6 +22,12 @@ func New(c *cli.Config) *cobra.Command {
 	return cmd
 }
 
+// info is the auth info as printed, with when the token expires.
+type info struct {
+	api.AuthInfoResponse `yaml:",inline"`
+	ExpiresAt            *time.Time `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+}
+
 func run(ctx context.Context, c *cli.Config) error {
 	var client = c.Client
 
@@ -28,7 +36,13 @@ func run(ctx context.Context, c *cli.Config) error {
 		return err
 	}
 
-	print.Print(res, func() {
+	// Reauthenticating may have replaced the token.
+	out := info{AuthInfoResponse: res}
+	if exp, ok := c.Client.TokenExpiry(); ok {
+		out.ExpiresAt = &exp
+	}
+
+	print.Print(out, func() {
 		var userStr string
 		if res.User == nil {
 			userStr = logger.Gray("<no user>")
@@ -37,6 +51,9 @@ func run(ctx context.Context, c *cli.Config) error {
 		}
 		logger.Log("  Signed in as %s", logger.Blue(userStr))
 		logger.Log("  Using team %s (ID: %s)", logger.Blue(res.Team.Name), res.Team.ID)
+		if out.ExpiresAt != nil {
+			logger.Log("  Login expires %s (in %s)", out.ExpiresAt.Local().Format(time.RFC1123), time.Until(*out.ExpiresAt).Round(time.Minute))
+		}
 	})
 
 	return nil
@@ -3,7 +3,10 @@ package login
 import (
 	"context"
 	"errors"
+	"sync"
+	"time"
 
+	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/pkg/analytics"
 	"github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/cli"
@@ -16,19 +19,31 @@ import (
 
 // New returns a new login command.
 func New(c *cli.Config) *cobra.Command {
+	var noBrowser bool
 	cmd := &cobra.Command{
 		Use:   "login",
 		Short: "Login to Airplane",
+		Example: heredoc.Doc(`
+			$ airplane login
+			$ airplane login --no-browser
+		`),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return run(cmd.Root().Context(), c)
+			return run(cmd.Root().Context(), c, noBrowser)
 		},
 	}
+	cmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Log in without opening a browser, for example on a remote machine. The token is pasted back instead.")
 	return cmd
 }
 
 // Run runs the login command.
-func run(ctx context.Context, c *cli.Config) error {
-	if err := login(ctx, c); err != nil {
+func run(ctx context.Context, c *cli.Config, noBrowser bool) error {
+	var err error
+	if noBrowser {
+		err = loginWithoutBrowser(ctx, c)
+	} else {
+		err = login(ctx, c)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -41,14 +56,24 @@ var (
 	ErrLoggedOut = errors.New("you are not logged in. To login, run:\n    airplane login")
 )
 
+// expiryWarning is how long before a token expires to warn about it.
+const expiryWarning = 24 * time.Hour
+
 // validateToken returns a boolean indicating whether or not the current
 // client token is valid.
 func validateToken(ctx context.Context, c *cli.Config) (bool, error) {
 	if c.Client.Token == "" {
 		return false, nil
 	}
+	if c.Client.TokenExpired() {
+		logger.Debug("Found an expired token. Re-authenticating.")
+		return false, nil
+	}
 
-	_, err := c.Client.AuthInfo(ctx)
+	// The caller asks before logging in again, so don't reauthenticate here.
+	client := *c.Client
+	client.Reauthenticate = nil
+	_, err := client.AuthInfo(ctx)
 	if e, ok := err.(api.Error); ok && e.Code == 401 {
 		logger.Debug("Found an expired token. Re-authenticating.")
 		return false, nil
@@ -56,21 +81,84 @@ func validateToken(ctx context.Context, c *cli.Config) (bool, error) {
 		return false, err
 	}
 
+	if exp, ok := c.Client.TokenExpiry(); ok && time.Until(exp) < expiryWarning {
+		logger.Warning("Your login expires in %s, run `airplane login` to renew it.", time.Until(exp).Round(time.Minute))
+	}
+
 	return true, nil
 }
 
-func validateAPIKey(ctx context.Context) bool {
-	return conf.GetAPIKey() != "" && conf.GetTeamID() != ""
+// Reauthenticate returns a function that logs in again when the token of
+// c.Client expires mid-command, so that the command can carry on.
+//
+// It returns an error if the CLI cannot prompt.
+func Reauthenticate(c *cli.Config) func(ctx context.Context, expired string) (string, error) {
+	var mu sync.Mutex
+	return func(ctx context.Context, expired string) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Concurrent requests may fail with the same token, only log in once.
+		if c.Client.Token != expired {
+			return c.Client.Token, nil
+		}
+		if !utils.CanPrompt() {
+			return "", ErrLoggedOut
+		}
+
+		logger.Warning("Your login has expired, logging in again...")
+		if err := login(ctx, c); err != nil {
+			return "", err
+		}
+		return c.Client.Token, nil
+	}
+}
+
+// LoadToken returns a function that reads the token of the profile or host
+// in use from the credential store, so that the store is only opened by
+// commands that call the API.
+func LoadToken(c *cli.Config) func(ctx context.Context) (string, error) {
+	var mu sync.Mutex
+	var loaded bool
+	return func(ctx context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if loaded || c.Client.Token != "" {
+			return c.Client.Token, nil
+		}
+		store, err := c.CredentialStore()
+		if err != nil {
+			return "", err
+		}
+		token, err := conf.GetToken(store, c.Profile, c.Client.Host)
+		if err != nil {
+			return "", err
+		}
+		c.Client.Token, loaded = token, true
+		return token, nil
+	}
+}
+
+func validateAPIKey(ctx context.Context, c *cli.Config) bool {
+	return c.Client.APIKey != "" && c.Client.TeamID != ""
 }
 
 func EnsureLoggedIn(ctx context.Context, c *cli.Config) error {
+	if c.Client.Token == "" && c.Client.LoadToken != nil {
+		// An API key can be used instead if the token cannot be read.
+		if _, err := c.Client.LoadToken(ctx); err != nil && !validateAPIKey(ctx, c) {
+			return err
+		}
+	}
+
 	if ok, err := validateToken(ctx, c); err != nil {
 		return err
 	} else if ok {
 		return nil
 	}
 
-	if ok := validateAPIKey(ctx); ok {
+	if ok := validateAPIKey(ctx, c); ok {
 		return nil
 	}
 
@@ -110,19 +198,67 @@ func login(ctx context.Context, c *cli.Config) error {
 		return ctx.Err()
 
 	case token := <-srv.Token():
-		c.Client.Token = token
-		cfg, err := conf.ReadDefault()
-		if err != nil && !errors.Is(err, conf.ErrMissing) {
-			return err
-		}
-		if cfg.Tokens == nil {
-			cfg.Tokens = map[string]string{}
-		}
-		cfg.Tokens[c.Client.Host] = token
-		if err := conf.WriteDefault(cfg); err != nil {
-			return err
+		return saveToken(c, token)
+	}
+}
+
+// loginWithoutBrowser logs in by asking the user to visit the login
+// URL on any machine, and paste back the token it redirects with.
+func loginWithoutBrowser(ctx context.Context, c *cli.Config) error {
+	if !utils.CanPrompt() {
+		return errors.New("logging in with --no-browser requires a terminal")
+	}
+
+	state, err := token.NewState()
+	if err != nil {
+		return err
+	}
+	// Nothing listens on this URL: once logged in, the browser fails to
+	// load it and the user copies it from the address bar instead.
+	redirect := "http://127.0.0.1/" + state
+
+	logger.Log(heredoc.Docf(`
+		Visit this URL in a browser to log in:
+
+		    %s
+
+		Once logged in, your browser will fail to load a page on 127.0.0.1.
+		Copy the URL of that page from the address bar and paste it below.
+	`, c.Client.LoginURL(redirect)))
+
+	input, err := utils.Password("URL or token:")
+	if err != nil {
+		return err
+	}
+	tkn, err := token.Parse(input, state)
+	if err != nil {
+		return err
+	}
+
+	// Make sure that the token is valid before storing it.
+	client := *c.Client
+	client.Token = tkn
+	client.Reauthenticate = nil
+	if _, err := client.AuthInfo(ctx); err != nil {
+		if e, ok := err.(api.Error); ok && e.Code == 401 {
+			return errors.New("the token is not valid, please try again")
 		}
+		return err
 	}
 
-	return nil
+	return saveToken(c, tkn)
+}
+
+// saveToken sets the token of the client and stores it.
+func saveToken(c *cli.Config, token string) error {
+	c.Client.Token = token
+	cfg, err := conf.ReadDefault()
+	if err != nil && !errors.Is(err, conf.ErrMissing) {
+		return err
+	}
+	store, err := c.CredentialStore()
+	if err != nil {
+		return err
+	}
+	return store.SetToken(conf.TokenKey(cfg, c.Profile, c.Client.Host), token)
 }
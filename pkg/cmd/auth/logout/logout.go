@@ -28,9 +28,11 @@ func run(ctx context.Context, c *cli.Config) error {
 			return err
 		}
 
-		delete(cfg.Tokens, c.Client.Host)
-
-		if err := conf.WriteDefault(cfg); err != nil {
+		store, err := c.CredentialStore()
+		if err != nil {
+			return err
+		}
+		if err := store.DeleteToken(conf.TokenKey(cfg, c.Profile, c.Client.Host)); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,107 @@
+package add
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root    *cli.Config
+	name    string
+	profile conf.Profile
+	apiKey  string
+}
+
+// New returns a new add command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{root: c}
+
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Adds a profile",
+		Long: heredoc.Doc(`
+			Adds a profile.
+
+			The global --host and --output flags set the host and default output format
+			of the profile. To authenticate with a token, login with the profile once
+			it is added.
+		`),
+		Example: heredoc.Doc(`
+			airplane auth profiles add staging --host api.airstage.app
+			airplane login --profile staging
+
+			airplane auth profiles add ci --api-key <key> --team-id <id> --output json
+			airplane auth profiles add prod --env AP_GIT_REPO=github.com/org/repo
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.name = args[0]
+			// The global --host and --output flags set the host and output of the profile.
+			if cmd.Flags().Changed("host") {
+				cfg.profile.Host = c.Client.Host
+			}
+			if cmd.Flags().Changed("output") {
+				output, err := cmd.Flags().GetString("output")
+				if err != nil {
+					return err
+				}
+				cfg.profile.Output = output
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.apiKey, "api-key", "", "API key to authenticate with.")
+	cmd.Flags().StringVar(&cfg.profile.TeamID, "team-id", "", "ID of the team to use with the API key.")
+	cmd.Flags().StringToStringVar(&cfg.profile.Env, "env", nil, "Env var of the profile, as KEY=VALUE. Can be repeated.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	if (cfg.apiKey == "") != (cfg.profile.TeamID == "") {
+		return errors.New("--api-key and --team-id must be used together")
+	}
+
+	// The API key is kept in the credential store. Opening the store may
+	// move tokens out of the config, so open it first.
+	var store conf.CredentialStore
+	if cfg.apiKey != "" {
+		var err error
+		if store, err = cfg.root.CredentialStore(); err != nil {
+			return err
+		}
+	}
+	c, err := conf.ReadDefault()
+	if err != nil && !errors.Is(err, conf.ErrMissing) {
+		return err
+	}
+	if _, ok := c.Profiles[cfg.name]; ok {
+		return errors.Errorf("profile %q already exists, remove it first to replace it", cfg.name)
+	}
+
+	if c.Profiles == nil {
+		c.Profiles = map[string]conf.Profile{}
+	}
+	c.Profiles[cfg.name] = cfg.profile
+	if err := conf.WriteDefault(c); err != nil {
+		return err
+	}
+	if store != nil {
+		if err := store.SetToken(conf.CredentialKey{Profile: cfg.name, APIKey: true}, cfg.apiKey); err != nil {
+			return errors.Wrap(err, "storing API key")
+		}
+	}
+
+	logger.Log("Added profile %s.", logger.Bold(cfg.name))
+	if cfg.apiKey == "" {
+		logger.Log("To login with it, run:\n    airplane login --profile %s", cfg.name)
+	}
+	return nil
+}
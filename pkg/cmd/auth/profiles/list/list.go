@@ -0,0 +1,110 @@
+package list
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new list command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists profiles",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), c)
+		},
+	}
+	return cmd
+}
+
+// profile is a profile as printed, without its credentials.
+type profile struct {
+	Name     string   `json:"name" yaml:"name"`
+	Active   bool     `json:"active" yaml:"active"`
+	Default  bool     `json:"default" yaml:"default"`
+	Host     string   `json:"host,omitempty" yaml:"host,omitempty"`
+	TeamID   string   `json:"teamID,omitempty" yaml:"teamID,omitempty"`
+	Auth     string   `json:"auth" yaml:"auth"`
+	Output   string   `json:"output,omitempty" yaml:"output,omitempty"`
+	EnvNames []string `json:"env,omitempty" yaml:"env,omitempty"`
+}
+
+// Run runs the list command.
+func run(ctx context.Context, c *cli.Config) error {
+	cfg, err := conf.ReadDefault()
+	if err != nil && !errors.Is(err, conf.ErrMissing) {
+		return err
+	}
+
+	store, err := c.CredentialStore()
+	if err != nil {
+		return err
+	}
+	profiles := []profile{}
+	for name, p := range cfg.Profiles {
+		token, err := store.Token(conf.CredentialKey{Profile: name})
+		if err != nil {
+			return err
+		}
+		apiKey, err := store.Token(conf.CredentialKey{Profile: name, APIKey: true})
+		if err != nil {
+			return err
+		}
+		auth := "none"
+		switch {
+		case token != "":
+			auth = "token"
+		case apiKey != "":
+			auth = "api key"
+		}
+		var envNames []string
+		for k := range p.Env {
+			envNames = append(envNames, k)
+		}
+		sort.Strings(envNames)
+
+		profiles = append(profiles, profile{
+			Name:     name,
+			Active:   name == c.Profile,
+			Default:  name == cfg.DefaultProfile,
+			Host:     p.Host,
+			TeamID:   p.TeamID,
+			Auth:     auth,
+			Output:   p.Output,
+			EnvNames: envNames,
+		})
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+
+	print.Print(profiles, func() {
+		if len(profiles) == 0 {
+			logger.Log("There are no profiles, add one with:\n    airplane auth profiles add <name>")
+			return
+		}
+
+		tw := tablewriter.NewWriter(os.Stdout)
+		tw.SetBorder(false)
+		tw.SetHeader([]string{"", "name", "host", "team id", "auth", "output"})
+		for _, p := range profiles {
+			var marker string
+			if p.Active {
+				marker = "*"
+			}
+			tw.Append([]string{marker, p.Name, p.Host, p.TeamID, p.Auth, p.Output})
+		}
+		tw.Render()
+	})
+	return nil
+}
@@ -0,0 +1,46 @@
+package profiles
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/cmd/auth/profiles/add"
+	"github.com/airplanedev/cli/pkg/cmd/auth/profiles/list"
+	"github.com/airplanedev/cli/pkg/cmd/auth/profiles/remove"
+	"github.com/airplanedev/cli/pkg/cmd/auth/profiles/use"
+	"github.com/spf13/cobra"
+)
+
+// Annotation marks the commands that manage profiles, which
+// can run while the selected profile does not exist.
+const Annotation = "profiles"
+
+// New returns a new profiles command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "Manage profiles",
+		Long: heredoc.Doc(`
+			Manage profiles.
+
+			A profile is a named set of settings (host, credentials, team, output format
+			and env vars) that commands run with. It is selected with --profile, the
+			AIRPLANE_PROFILE env var or "airplane auth profiles use", and logging in with
+			a profile stores the token in the profile.
+		`),
+		Aliases: []string{"profile"},
+		Example: heredoc.Doc(`
+			airplane auth profiles add staging --host api.airstage.app
+			airplane auth profiles use staging
+			airplane auth profiles list
+			airplane auth profiles remove staging
+		`),
+		Annotations: map[string]string{Annotation: "true"},
+	}
+
+	cmd.AddCommand(add.New(c))
+	cmd.AddCommand(list.New(c))
+	cmd.AddCommand(remove.New(c))
+	cmd.AddCommand(use.New(c))
+
+	return cmd
+}
@@ -0,0 +1,56 @@
+package remove
+
+import (
+	"context"
+
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new remove command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove <name>",
+		Short:   "Removes a profile",
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), c, args[0])
+		},
+	}
+	return cmd
+}
+
+func run(ctx context.Context, c *cli.Config, name string) error {
+	// Opening the store may move tokens out of the config, so open it first.
+	store, err := c.CredentialStore()
+	if err != nil {
+		return err
+	}
+	cfg, err := conf.ReadDefault()
+	if err != nil && !errors.Is(err, conf.ErrMissing) {
+		return err
+	}
+	if _, ok := cfg.Profiles[name]; !ok {
+		return errors.Errorf("profile %q does not exist", name)
+	}
+
+	for _, key := range []conf.CredentialKey{{Profile: name}, {Profile: name, APIKey: true}} {
+		if err := store.DeleteToken(key); err != nil {
+			return err
+		}
+	}
+	delete(cfg.Profiles, name)
+	if cfg.DefaultProfile == name {
+		cfg.DefaultProfile = ""
+	}
+	if err := conf.WriteDefault(cfg); err != nil {
+		return err
+	}
+
+	logger.Log("Removed profile %s.", logger.Bold(name))
+	return nil
+}
@@ -0,0 +1,68 @@
+package use
+
+import (
+	"context"
+
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root  *cli.Config
+	name  string
+	unset bool
+}
+
+// New returns a new use command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{root: c}
+
+	cmd := &cobra.Command{
+		Use:   "use <name>",
+		Short: "Sets the default profile",
+		Long:  "Sets the profile that is used when none is selected with --profile or AIRPLANE_PROFILE.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				cfg.name = args[0]
+			} else if !cfg.unset {
+				return errors.New("expected a profile name: airplane auth profiles use <name>")
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().BoolVar(&cfg.unset, "unset", false, "Unset the default profile, so that no profile is used by default")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	c, err := conf.ReadDefault()
+	if err != nil && !errors.Is(err, conf.ErrMissing) {
+		return err
+	}
+
+	if cfg.unset {
+		c.DefaultProfile = ""
+	} else {
+		if _, ok := c.Profiles[cfg.name]; !ok {
+			return errors.Errorf("profile %q does not exist", cfg.name)
+		}
+		c.DefaultProfile = cfg.name
+	}
+
+	if err := conf.WriteDefault(c); err != nil {
+		return err
+	}
+
+	if cfg.unset {
+		logger.Log("Unset the default profile.")
+	} else {
+		logger.Log("Using profile %s by default.", logger.Bold(cfg.name))
+	}
+	return nil
+}
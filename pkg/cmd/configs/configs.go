@@ -4,8 +4,13 @@ import (
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/pkg/cli"
 	"github.com/airplanedev/cli/pkg/cmd/auth/login"
+	"github.com/airplanedev/cli/pkg/cmd/configs/delete"
+	"github.com/airplanedev/cli/pkg/cmd/configs/export"
 	"github.com/airplanedev/cli/pkg/cmd/configs/get"
+	"github.com/airplanedev/cli/pkg/cmd/configs/importcmd"
+	"github.com/airplanedev/cli/pkg/cmd/configs/list"
 	"github.com/airplanedev/cli/pkg/cmd/configs/set"
+	"github.com/airplanedev/cli/pkg/cmd/configs/tags"
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -19,6 +24,8 @@ func New(c *cli.Config) *cobra.Command {
 		Example: heredoc.Doc(`
 			$ airplane configs set my_database_url postgresql://my_database
 			$ airplane configs get my_config_name
+			$ airplane configs list --prefix db/
+			$ airplane configs export configs.env
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
@@ -27,6 +34,11 @@ func New(c *cli.Config) *cobra.Command {
 
 	cmd.AddCommand(set.New(c))
 	cmd.AddCommand(get.New(c))
+	cmd.AddCommand(list.New(c))
+	cmd.AddCommand(delete.New(c))
+	cmd.AddCommand(tags.New(c))
+	cmd.AddCommand(importcmd.New(c))
+	cmd.AddCommand(export.New(c))
 
 	return cmd
 }
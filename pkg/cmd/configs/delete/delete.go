@@ -0,0 +1,59 @@
+package delete
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/configs"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new delete command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <name>...",
+		Short: "Deletes one or more config variables",
+		Example: heredoc.Doc(`
+			# Delete the config variable without a tag
+			$ airplane configs delete db/url
+
+			# Delete a tagged config variable
+			$ airplane configs delete db/url:prod
+		`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), c, args)
+		},
+	}
+	return cmd
+}
+
+// Run runs the delete command.
+func run(ctx context.Context, c *cli.Config, names []string) error {
+	var client = c.Client
+
+	var nts []configs.NameTag
+	for _, name := range names {
+		nt, err := configs.ParseName(name)
+		if err == configs.ErrInvalidConfigName {
+			return errors.Errorf("invalid config name: %s - expected my_config or my_config:tag", name)
+		}
+		nts = append(nts, nt)
+	}
+
+	for _, nt := range nts {
+		logger.Log("  Deleting %s...", logger.Red(configs.JoinName(nt)))
+		if err := client.DeleteConfig(ctx, api.DeleteConfigRequest{
+			Name: nt.Name,
+			Tag:  nt.Tag,
+		}); err != nil {
+			return errors.Wrap(err, "delete config")
+		}
+	}
+	logger.Log("  Done.")
+	return nil
+}
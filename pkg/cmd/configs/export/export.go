@@ -0,0 +1,103 @@
+package export
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/configs"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root        *cli.Config
+	file        string
+	format      string
+	prefix      string
+	showSecrets bool
+}
+
+// New returns a new export command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{root: c}
+	cmd := &cobra.Command{
+		Use:   "export [file]",
+		Short: "Exports config variables to a .env or YAML file",
+		Example: heredoc.Doc(`
+			$ airplane configs export configs.env
+			$ airplane configs export --prefix db/ configs.yaml
+			$ airplane configs export --format yaml --show-secrets > configs.yaml
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 && args[0] != "-" {
+				cfg.file = args[0]
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+	cmd.Flags().StringVar(&cfg.format, "format", "", "Format of the file, env or yaml. Defaults to the extension of the file, or env.")
+	cmd.Flags().StringVar(&cfg.prefix, "prefix", "", "Only export config variables whose name starts with this prefix")
+	cmd.Flags().BoolVar(&cfg.showSecrets, "show-secrets", false, "Export the values of secrets instead of masking them")
+	return cmd
+}
+
+// Run runs the export command.
+func run(ctx context.Context, cfg config) error {
+	var client = cfg.root.Client
+
+	format := cfg.format
+	if format == "" {
+		format = configs.FormatFromPath(cfg.file)
+	}
+
+	resp, err := client.ListConfigs(ctx, api.ListConfigsRequest{ShowSecrets: cfg.showSecrets})
+	if err != nil {
+		return errors.Wrap(err, "list configs")
+	}
+
+	var fcs []configs.FileConfig
+	var masked int
+	for _, c := range resp.Configs {
+		if !strings.HasPrefix(c.Name, cfg.prefix) {
+			continue
+		}
+		value := c.Value
+		if c.IsSecret && !cfg.showSecrets {
+			value = configs.SecretPlaceholder
+			masked++
+		}
+		fcs = append(fcs, configs.FileConfig{
+			NameTag: configs.NameTag{Name: c.Name, Tag: c.Tag},
+			Value:   value,
+			Secret:  c.IsSecret,
+		})
+	}
+
+	var w io.Writer = os.Stdout
+	if cfg.file != "" {
+		f, err := os.OpenFile(cfg.file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return errors.Wrap(err, "creating file")
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := configs.WriteFile(w, fcs, format); err != nil {
+		return err
+	}
+
+	if cfg.file != "" {
+		logger.Log("Exported %d config variable(s) to %s.", len(fcs), cfg.file)
+	}
+	if masked > 0 {
+		logger.Warning("The values of %d secret(s) were masked, use --show-secrets to export them.", masked)
+	}
+	return nil
+}
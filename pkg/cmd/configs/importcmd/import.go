@@ -0,0 +1,81 @@
+package importcmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/configs"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root   *cli.Config
+	file   string
+	format string
+}
+
+// New returns a new import command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{root: c}
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Imports config variables from a .env or YAML file",
+		Long: heredoc.Doc(`
+			Imports config variables from a .env or YAML file, such as one written by
+			airplane configs export. Existing config variables are updated.
+
+			In .env files, a "# secret" comment marks the config variable on the next
+			line as a secret. Secrets that were exported without their value are skipped.
+		`),
+		Example: heredoc.Doc(`
+			$ airplane configs import configs.env
+			$ airplane configs import --format yaml configs.txt
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.file = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+	cmd.Flags().StringVar(&cfg.format, "format", "", "Format of the file, env or yaml. Defaults to the extension of the file, or env.")
+	return cmd
+}
+
+// Run runs the import command.
+func run(ctx context.Context, cfg config) error {
+	var client = cfg.root.Client
+
+	format := cfg.format
+	if format == "" {
+		format = configs.FormatFromPath(cfg.file)
+	}
+
+	f, err := os.Open(cfg.file)
+	if err != nil {
+		return errors.Wrap(err, "opening file")
+	}
+	defer f.Close()
+	fcs, err := configs.ReadFile(f, format)
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", cfg.file)
+	}
+
+	var skipped int
+	for _, c := range fcs {
+		if c.Secret && c.Value == configs.SecretPlaceholder {
+			logger.Warning("Skipping %s, its value was masked when it was exported.", configs.JoinName(c.NameTag))
+			skipped++
+			continue
+		}
+		if err := configs.SetConfig(ctx, client, c.NameTag, c.Value, c.Secret); err != nil {
+			return err
+		}
+	}
+
+	logger.Log("Imported %d config variable(s).", len(fcs)-skipped)
+	return nil
+}
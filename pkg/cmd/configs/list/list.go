@@ -0,0 +1,52 @@
+package list
+
+import (
+	"context"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new list command.
+func New(c *cli.Config) *cobra.Command {
+	var prefix string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists config variables",
+		Example: heredoc.Doc(`
+			$ airplane configs list
+			$ airplane configs list --prefix db/
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), c, prefix)
+		},
+	}
+	cmd.Flags().StringVar(&prefix, "prefix", "", "Only list config variables whose name starts with this prefix")
+	return cmd
+}
+
+// Run runs the list command.
+func run(ctx context.Context, c *cli.Config, prefix string) error {
+	var client = c.Client
+
+	resp, err := client.ListConfigs(ctx, api.ListConfigsRequest{})
+	if err != nil {
+		return errors.Wrap(err, "list configs")
+	}
+
+	configs := []api.Config{}
+	for _, cfg := range resp.Configs {
+		if strings.HasPrefix(cfg.Name, prefix) {
+			configs = append(configs, cfg)
+		}
+	}
+
+	print.Configs(configs)
+	return nil
+}
@@ -0,0 +1,47 @@
+package tags
+
+import (
+	"context"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new tags command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tags <name>",
+		Short: "Lists the tags of a config variable",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), c, args[0])
+		},
+	}
+	return cmd
+}
+
+// Run runs the tags command.
+func run(ctx context.Context, c *cli.Config, name string) error {
+	var client = c.Client
+
+	resp, err := client.ListConfigs(ctx, api.ListConfigsRequest{})
+	if err != nil {
+		return errors.Wrap(err, "list configs")
+	}
+
+	configs := []api.Config{}
+	for _, cfg := range resp.Configs {
+		if cfg.Name == name {
+			configs = append(configs, cfg)
+		}
+	}
+	if len(configs) == 0 {
+		return errors.Errorf("config variable %s does not exist", name)
+	}
+
+	print.Configs(configs)
+	return nil
+}
@@ -2,6 +2,7 @@ package root
 
 import (
 	"errors"
+	"fmt"
 	"os"
 
 	"github.com/MakeNowJust/heredoc"
@@ -12,6 +13,7 @@ import (
 	"github.com/airplanedev/cli/pkg/cmd/auth"
 	"github.com/airplanedev/cli/pkg/cmd/auth/login"
 	"github.com/airplanedev/cli/pkg/cmd/auth/logout"
+	"github.com/airplanedev/cli/pkg/cmd/auth/profiles"
 	"github.com/airplanedev/cli/pkg/cmd/configs"
 	"github.com/airplanedev/cli/pkg/cmd/runs"
 	"github.com/airplanedev/cli/pkg/cmd/tasks"
@@ -24,6 +26,7 @@ import (
 	"github.com/airplanedev/cli/pkg/logger"
 	"github.com/airplanedev/cli/pkg/print"
 	"github.com/airplanedev/cli/pkg/trap"
+	"github.com/airplanedev/cli/pkg/utils"
 	isatty "github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
@@ -44,11 +47,11 @@ func New() *cobra.Command {
 			airplane deploy ./path/to/script
 		`),
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			if c, err := conf.ReadDefault(); err == nil {
-				cfg.Client.Token = c.Tokens[cfg.Client.Host]
+			if err := applyProfile(cmd, cfg, &output); err != nil {
+				return err
 			}
-			cfg.Client.APIKey = conf.GetAPIKey()
-			cfg.Client.TeamID = conf.GetTeamID()
+			cfg.Client.LoadToken = login.LoadToken(cfg)
+			cfg.Client.Reauthenticate = login.Reauthenticate(cfg)
 			if err := analytics.Init(cfg); err != nil {
 				logger.Debug("error in analytics.Init: %v", err)
 			}
@@ -91,6 +94,7 @@ func New() *cobra.Command {
 
 	// Persistent flags, set globally to all commands.
 	cmd.PersistentFlags().StringVarP(&cfg.Client.Host, "host", "", api.Host, "Airplane API Host.")
+	cmd.PersistentFlags().StringVar(&cfg.Profile, "profile", "", "The profile to use, defaults to $AIRPLANE_PROFILE or the default profile.")
 	defaultFormat := "table"
 	if !isatty.IsTerminal(os.Stdout.Fd()) {
 		defaultFormat = "json"
@@ -117,3 +121,80 @@ func New() *cobra.Command {
 
 	return cmd
 }
+
+// applyProfile selects the profile to use and configures cfg and output from
+// it, along with the credential store, and credentials from env vars.
+//
+// Flags and env vars take precedence over the settings of the profile.
+func applyProfile(cmd *cobra.Command, cfg *cli.Config, output *string) error {
+	c, err := conf.ReadDefault()
+	if err != nil && !errors.Is(err, conf.ErrMissing) {
+		logger.Debug("error reading config: %v", err)
+	}
+
+	if cfg.Profile == "" {
+		cfg.Profile = conf.GetProfile()
+	}
+	if cfg.Profile == "" {
+		cfg.Profile = c.DefaultProfile
+	}
+
+	var p conf.Profile
+	if cfg.Profile != "" {
+		var ok bool
+		if p, ok = c.Profiles[cfg.Profile]; !ok && !isProfilesCommand(cmd) {
+			return fmt.Errorf("profile %q does not exist, add it with:\n    airplane auth profiles add %s", cfg.Profile, cfg.Profile)
+		}
+	}
+
+	cfg.Env = p.Env
+	if p.Host != "" && !cmd.Flags().Changed("host") {
+		cfg.Client.Host = p.Host
+	}
+	if p.Output != "" && !cmd.Flags().Changed("output") {
+		*output = p.Output
+	}
+
+	cfg.OpenCredentials = func() (conf.CredentialStore, error) {
+		return conf.OpenCredentialStore(c, conf.CredentialStoreOptions{
+			Passphrase: func(confirm bool) (string, error) {
+				if !utils.CanPrompt() {
+					return "", conf.ErrPassphraseRequired
+				}
+				if confirm {
+					return utils.Password("Confirm the passphrase:")
+				}
+				return utils.Password("Passphrase of your Airplane credentials:")
+			},
+		})
+	}
+	cfg.Client.APIKey = cfg.Getenv("AP_API_KEY")
+	if cfg.Client.APIKey == "" && p.TeamID != "" {
+		// Profiles with a team ID authenticate with an API key, which
+		// is kept in the credential store.
+		store, err := cfg.CredentialStore()
+		if err != nil {
+			return err
+		}
+		if cfg.Client.APIKey, err = store.Token(conf.CredentialKey{Profile: cfg.Profile, APIKey: true}); err != nil {
+			return fmt.Errorf("loading API key: %w", err)
+		}
+	}
+	cfg.Client.TeamID = cfg.Getenv("AP_TEAM_ID")
+	if cfg.Client.TeamID == "" {
+		cfg.Client.TeamID = p.TeamID
+	}
+
+	return nil
+}
+
+// isProfilesCommand returns true if cmd manages profiles, in which case
+// the selected profile does not need to exist.
+func isProfilesCommand(cmd *cobra.Command) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd.Annotations[profiles.Annotation] != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package cancel
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cancel command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel <id>",
+		Short: "Cancel a run",
+		Example: heredoc.Doc(`
+			airplane runs cancel <id>
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), c, args[0])
+		},
+	}
+	return cmd
+}
+
+// Run runs the cancel command.
+func run(ctx context.Context, c *cli.Config, id string) error {
+	var client = c.Client
+
+	logger.Log("  Cancelling run %s...", logger.Blue(id))
+	if err := client.CancelRun(ctx, id); err != nil {
+		return errors.Wrap(err, "cancel run")
+	}
+	logger.Log("  Done!")
+
+	return nil
+}
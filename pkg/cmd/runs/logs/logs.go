@@ -0,0 +1,119 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	id     string
+	follow bool
+	since  utils.TimeValue
+	level  string
+}
+
+// New returns a new logs command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "logs <id>",
+		Short: "Print the logs of a run",
+		Example: heredoc.Doc(`
+			airplane runs logs <id>
+			airplane runs logs <id> --follow
+			airplane runs logs <id> --since 10m
+			airplane runs logs <id> -o json
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.id = args[0]
+			return run(cmd.Root().Context(), c, cfg)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&cfg.follow, "follow", "f", false, "Keep streaming logs until the run stops")
+	cmd.Flags().Var(&cfg.since, "since", "Include only logs written after the given time, or within the given duration such as 10m")
+	cmd.Flags().StringVar(&cfg.level, "level", "", "Include only logs of the given level, one of info or debug (debug logs require --debug)")
+
+	return cmd
+}
+
+// Run runs the logs command.
+func run(ctx context.Context, c *cli.Config, cfg config) error {
+	switch api.LogLevel(cfg.level) {
+	case "", api.LogLevelInfo, api.LogLevelDebug:
+	default:
+		return fmt.Errorf("invalid --level %q, expected one of info or debug", cfg.level)
+	}
+
+	if cfg.follow {
+		return follow(ctx, c, cfg)
+	}
+
+	var client = c.Client
+	var prevToken string
+
+	for {
+		resp, err := client.GetLogs(ctx, cfg.id, prevToken)
+		if err != nil {
+			return errors.Wrap(err, "get logs")
+		}
+		if len(resp.Logs) == 0 {
+			return nil
+		}
+
+		api.SortLogs(resp.Logs)
+		print.Logs(filter(resp.Logs, cfg))
+		prevToken = resp.PrevPageToken
+	}
+}
+
+// Follow prints logs as they are written until the run stops.
+//
+// Interrupting the command stops following without cancelling the run.
+func follow(ctx context.Context, c *cli.Config, cfg config) error {
+	var w = c.Client.WatchRun(ctx, cfg.id)
+
+	for {
+		state := w.Next()
+		if err := state.Err(); err != nil {
+			return err
+		}
+
+		print.Logs(filter(state.Logs, cfg))
+
+		// The watcher reports the final state once more after the run
+		// stopped, so keep going until there are no more logs to print.
+		if state.Stopped() && len(state.Logs) == 0 {
+			return nil
+		}
+	}
+}
+
+// Filter returns the logs matching the configured level and since time.
+func filter(logs []api.LogItem, cfg config) []api.LogItem {
+	var since = time.Time(cfg.since)
+	var ret = make([]api.LogItem, 0, len(logs))
+
+	for _, l := range logs {
+		if cfg.level != "" && l.Level != api.LogLevel(cfg.level) {
+			continue
+		}
+		if !since.IsZero() && l.Timestamp.Before(since) {
+			continue
+		}
+		ret = append(ret, l)
+	}
+
+	return ret
+}
@@ -0,0 +1,98 @@
+package rerun
+
+import (
+	"context"
+	"flag"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/cmd/tasks/execute"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/params"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	id   string
+	args []string
+}
+
+// New returns a new rerun command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "rerun <id> [-- <parameters...>]",
+		Short: "Run a task again with the parameters of a previous run",
+		Long: heredoc.Doc(`
+			Runs the task of a previous run again, with the same parameter values.
+
+			Parameters passed as flags after -- override the values of the previous run.
+			The new run is followed like with airplane execute.
+		`),
+		Example: heredoc.Doc(`
+			airplane runs rerun <id>
+			airplane runs rerun <id> -- --name Alice
+		`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.id = args[0]
+			cfg.args = args[1:]
+			return run(cmd.Root().Context(), c, cfg)
+		},
+	}
+	return cmd
+}
+
+// Run runs the rerun command.
+func run(ctx context.Context, c *cli.Config, cfg config) error {
+	var client = c.Client
+
+	resp, err := client.GetRun(ctx, cfg.id)
+	if err != nil {
+		return errors.Wrap(err, "get run")
+	}
+	prev := resp.Run
+
+	task, err := getTask(ctx, client, prev.TaskID)
+	if err != nil {
+		return err
+	}
+
+	req := api.RunTaskRequest{
+		TaskID:      task.ID,
+		ParamValues: api.Values{},
+	}
+	for k, v := range prev.ParamValues {
+		req.ParamValues[k] = v
+	}
+	if err := params.Flags(cfg.args, task, req.ParamValues); errors.Is(err, flag.ErrHelp) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := params.Validate(task, req.ParamValues); err != nil {
+		return err
+	}
+	if err := params.Upload(req.ParamValues, params.APIUploader(ctx, client)); err != nil {
+		return err
+	}
+
+	logger.Log("Re-running %s task: %s", logger.Bold(task.Name), logger.Gray(client.RunURL(prev.RunID)))
+
+	return execute.Watch(ctx, c, task, req)
+}
+
+// getTask returns the task with the given ID.
+func getTask(ctx context.Context, client *api.Client, id string) (api.Task, error) {
+	task, err := client.GetTaskByID(ctx, id)
+	if err, ok := err.(api.Error); ok && err.Code == 404 {
+		return api.Task{}, errors.Errorf("task %s of the run was not found, it may have been deleted", id)
+	}
+	if err != nil {
+		return api.Task{}, errors.Wrap(err, "get task")
+	}
+	return task, nil
+}
@@ -4,8 +4,12 @@ import (
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/pkg/cli"
 	"github.com/airplanedev/cli/pkg/cmd/auth/login"
+	"github.com/airplanedev/cli/pkg/cmd/runs/cancel"
 	"github.com/airplanedev/cli/pkg/cmd/runs/get"
 	"github.com/airplanedev/cli/pkg/cmd/runs/list"
+	"github.com/airplanedev/cli/pkg/cmd/runs/logs"
+	"github.com/airplanedev/cli/pkg/cmd/runs/rerun"
+	"github.com/airplanedev/cli/pkg/cmd/runs/wait"
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -20,6 +24,10 @@ func New(c *cli.Config) *cobra.Command {
 		Example: heredoc.Doc(`
 			airplane runs list --task my-task
 			airplane runs get <id>
+			airplane runs cancel <id>
+			airplane runs logs <id> --follow
+			airplane runs rerun <id> -- --name Alice
+			airplane runs wait <id> --timeout 10m
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
@@ -28,6 +36,10 @@ func New(c *cli.Config) *cobra.Command {
 
 	cmd.AddCommand(list.New(c))
 	cmd.AddCommand(get.New(c))
+	cmd.AddCommand(cancel.New(c))
+	cmd.AddCommand(logs.New(c))
+	cmd.AddCommand(rerun.New(c))
+	cmd.AddCommand(wait.New(c))
 
 	return cmd
 }
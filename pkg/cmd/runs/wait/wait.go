@@ -0,0 +1,119 @@
+package wait
+
+import (
+	"context"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of runs that did not succeed, so that
+// scripts can tell them apart from other errors.
+const (
+	exitCodeFailed    = 2
+	exitCodeCancelled = 3
+	exitCodeTimeout   = 4
+)
+
+// pollInterval is the interval at which the status of the run is checked.
+var pollInterval = 2 * time.Second
+
+type config struct {
+	id      string
+	timeout time.Duration
+}
+
+// New returns a new wait command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "wait <id>",
+		Short: "Wait for a run to stop",
+		Long: heredoc.Docf(`
+			Waits for a run to stop and prints it.
+
+			The command exits with status 0 if the run succeeded, %d if it failed,
+			%d if it was cancelled and %d if it did not stop within --timeout.
+		`, exitCodeFailed, exitCodeCancelled, exitCodeTimeout),
+		Example: heredoc.Doc(`
+			airplane runs wait <id>
+			airplane runs wait <id> --timeout 10m -o json
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.id = args[0]
+			return run(cmd.Root().Context(), c, cfg)
+		},
+	}
+
+	cmd.Flags().DurationVar(&cfg.timeout, "timeout", 0, "Maximum time to wait for, such as 10m. Waits forever by default.")
+
+	return cmd
+}
+
+// Run runs the wait command.
+func run(ctx context.Context, c *cli.Config, cfg config) error {
+	var client = c.Client
+
+	if cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		resp, err := client.GetRun(ctx, cfg.id)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return timeoutError(cfg)
+		} else if err != nil {
+			return errors.Wrap(err, "get run")
+		}
+
+		run := resp.Run
+		if (api.RunState{Status: run.Status}).Stopped() {
+			print.Print(run, func() {
+				logger.Log("Run %s %s.", logger.Bold(run.RunID), run.Status)
+			})
+			return runError(run)
+		}
+		logger.Debug("Run %s is %s", run.RunID, run.Status)
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return timeoutError(cfg)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// runError returns the exit code error of a run that stopped.
+func runError(run api.Run) error {
+	switch run.Status {
+	case api.RunFailed:
+		return utils.ExitCodeError{Code: exitCodeFailed, Err: errors.New("Run has failed")}
+	case api.RunCancelled:
+		return utils.ExitCodeError{Code: exitCodeCancelled, Err: errors.New("Run has been cancelled")}
+	}
+	return nil
+}
+
+func timeoutError(cfg config) error {
+	return utils.ExitCodeError{
+		Code: exitCodeTimeout,
+		Err:  errors.Errorf("run %s did not stop within %s", cfg.id, cfg.timeout),
+	}
+}
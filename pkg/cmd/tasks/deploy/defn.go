@@ -9,7 +9,6 @@ import (
 	"github.com/airplanedev/cli/pkg/analytics"
 	"github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/build"
-	"github.com/airplanedev/cli/pkg/conf"
 	"github.com/airplanedev/cli/pkg/logger"
 	"github.com/airplanedev/cli/pkg/taskdir"
 	"github.com/airplanedev/cli/pkg/taskdir/definitions"
@@ -19,88 +18,140 @@ import (
 	"github.com/pkg/errors"
 )
 
-// deployFromTaskDefn deploys from a task definition file.
-func deployFromTaskDefn(ctx context.Context, cfg config) error {
-	client := cfg.client
-	dir, err := taskdir.Open(cfg.paths[0], true)
+// errUnchanged is returned by getTaskConfigFromDefnFile for definitions
+// whose task root has none of the changed files.
+var errUnchanged = errors.New("no changed files")
+
+// getTaskConfigFromDefnFile opens the task definition at path and returns its task config,
+// like getTaskConfigFromDefn.
+//
+// With --changed-files, definitions whose task root has none of the changed files are
+// skipped with errUnchanged before their task is fetched or created.
+func getTaskConfigFromDefnFile(ctx context.Context, cfg config, path string) (taskConfig, bool, error) {
+	dir, err := taskdir.Open(path, true)
 	if err != nil {
-		return err
+		return taskConfig{}, false, err
 	}
 	defer dir.Close()
 
+	if len(cfg.changedFiles) > 0 {
+		contains, err := containsFile(dir.DefinitionRootPath(), cfg.changedFiles)
+		if err != nil {
+			return taskConfig{}, false, err
+		}
+		if !contains {
+			return taskConfig{}, false, errUnchanged
+		}
+	}
+
+	return getTaskConfigFromDefn(ctx, cfg, dir)
+}
+
+// getTaskConfigFromDefn returns the task and associated information of a task definition.
+//
+// If the task does not exist yet, the user is asked whether to create it, and false is
+// returned if it should be skipped. In a dry-run, the task is not created and the
+// returned config has a zero task instead.
+func getTaskConfigFromDefn(ctx context.Context, cfg config, dir taskdir.TaskDirectory) (taskConfig, bool, error) {
+	client := cfg.client
+
 	def, err := dir.ReadDefinition_0_3()
 	if err != nil {
-		return err
+		return taskConfig{}, false, err
 	}
 
 	task, err := client.GetTask(ctx, def.Slug)
 	if _, ok := err.(*api.TaskMissingError); ok {
-		if !utils.CanPrompt() {
-			logger.Warning(`Task with slug %s does not exist, skipping deploy.`, def.Slug)
-			return nil
+		if cfg.dryRun {
+			task = api.Task{}
+		} else if task, ok, err = createTaskFromDefn(ctx, cfg, def); err != nil || !ok {
+			return taskConfig{}, false, err
 		}
+	} else if err != nil {
+		return taskConfig{}, false, errors.Wrap(err, "getting task")
+	}
 
-		question := fmt.Sprintf("Task with slug %s does not exist. Would you like to create a new task?", def.Slug)
-		if ok, err := utils.ConfirmWithAssumptions(question, cfg.assumeYes, cfg.assumeNo); err != nil {
-			return err
-		} else if !ok {
-			// User answered "no", so bail here.
-			return nil
-		}
+	utr, err := def.GetUpdateTaskRequest(ctx, client, nil)
+	if err != nil {
+		return taskConfig{}, false, err
+	}
 
-		logger.Log("Creating task...")
-		utr, err := def.GetUpdateTaskRequest(ctx, client, nil)
-		if err != nil {
-			return err
-		}
+	root := dir.DefinitionRootPath()
+	taskFilePath := ""
+	entrypoint, err := def.Entrypoint()
+	if err == definitions.ErrNoEntrypoint {
+		// nothing
+	} else if err != nil {
+		return taskConfig{}, false, err
+	} else {
+		taskFilePath = filepath.Join(root, entrypoint)
+	}
 
-		_, err = client.CreateTask(ctx, api.CreateTaskRequest{
-			Slug:             utr.Slug,
-			Name:             utr.Name,
-			Description:      utr.Description,
-			Image:            utr.Image,
-			Command:          utr.Command,
-			Arguments:        utr.Arguments,
-			Parameters:       utr.Parameters,
-			Constraints:      utr.Constraints,
-			Env:              utr.Env,
-			ResourceRequests: utr.ResourceRequests,
-			Resources:        utr.Resources,
-			Kind:             utr.Kind,
-			KindOptions:      utr.KindOptions,
-			Repo:             utr.Repo,
-			Timeout:          utr.Timeout,
-		})
-		if err != nil {
-			return errors.Wrapf(err, "creating task %s", def.Slug)
-		}
+	return taskConfig{
+		taskRoot:         root,
+		workingDirectory: root,
+		taskFilePath:     taskFilePath,
+		defnFile:         dir.DefinitionPath(),
+		task:             task,
+		def:              &def,
+		kind:             utr.Kind,
+		kindOptions:      utr.KindOptions,
+	}, true, nil
+}
 
-		task, err = client.GetTask(ctx, def.Slug)
-		if err != nil {
-			return errors.Wrap(err, "fetching created task")
-		}
-	} else if err != nil {
-		return errors.Wrap(err, "getting task")
+// createTaskFromDefn asks the user whether to create the task of a definition, and
+// creates it. It returns false if the task was not created.
+func createTaskFromDefn(ctx context.Context, cfg config, def definitions.Definition_0_3) (api.Task, bool, error) {
+	client := cfg.client
+
+	if !utils.CanPrompt() {
+		logger.Warning(`Task with slug %s does not exist, skipping deploy.`, def.Slug)
+		return api.Task{}, false, nil
 	}
 
-	tc, err := getTaskConfigFromDefn(ctx, *client, def, task, dir.DefinitionRootPath())
+	question := fmt.Sprintf("Task with slug %s does not exist. Would you like to create a new task?", def.Slug)
+	if ok, err := utils.ConfirmWithAssumptions(question, cfg.assumeYes, cfg.assumeNo); err != nil {
+		return api.Task{}, false, err
+	} else if !ok {
+		// User answered "no", so bail here.
+		return api.Task{}, false, nil
+	}
+
+	logger.Log("Creating task %s...", def.Slug)
+	utr, err := def.GetUpdateTaskRequest(ctx, client, nil)
 	if err != nil {
-		return err
+		return api.Task{}, false, err
 	}
 
-	if err := deploySingleTaskFromTaskDefn(ctx, cfg, tc); err != nil {
-		logger.Log("\n" + logger.Bold(tc.def.GetSlug()))
-		logger.Log("Status: " + logger.Bold(logger.Red("failed")))
-		logger.Error(err.Error())
-		return err
+	_, err = client.CreateTask(ctx, api.CreateTaskRequest{
+		Slug:             utr.Slug,
+		Name:             utr.Name,
+		Description:      utr.Description,
+		Image:            utr.Image,
+		Command:          utr.Command,
+		Arguments:        utr.Arguments,
+		Parameters:       utr.Parameters,
+		Constraints:      utr.Constraints,
+		Env:              utr.Env,
+		ResourceRequests: utr.ResourceRequests,
+		Resources:        utr.Resources,
+		Kind:             utr.Kind,
+		KindOptions:      utr.KindOptions,
+		Repo:             utr.Repo,
+		Timeout:          utr.Timeout,
+	})
+	if err != nil {
+		return api.Task{}, false, errors.Wrapf(err, "creating task %s", def.Slug)
 	}
-	logger.Log("\n" + logger.Bold(tc.def.GetSlug()))
-	logger.Log("Status: %s", logger.Bold(logger.Green("succeeded")))
-	logger.Log("Execute the task: %s", client.TaskURL(tc.def.GetSlug()))
-	return nil
+
+	task, err := client.GetTask(ctx, def.Slug)
+	if err != nil {
+		return api.Task{}, false, errors.Wrap(err, "fetching created task")
+	}
+	return task, true, nil
 }
 
-func deploySingleTaskFromTaskDefn(ctx context.Context, cfg config, tc taskConfig) (rErr error) {
+func (d *scriptDeployer) deploySingleTaskFromTaskDefn(ctx context.Context, cfg config, tc taskConfig) (_ string, rErr error) {
 	client := cfg.client
 	props := taskDeployedProps{
 		from: "defn",
@@ -121,19 +172,11 @@ func deploySingleTaskFromTaskDefn(ctx context.Context, cfg config, tc taskConfig
 
 	task := tc.task
 
+	props.kind = tc.kind
 	props.taskSlug = tc.def.GetSlug()
 	props.taskID = task.ID
 	props.taskName = task.Name
 
-	logger.Log(logger.Bold(tc.task.Slug))
-	logger.Log("Type: %s", tc.kind)
-	logger.Log("Root directory: %s", relpath(tc.taskRoot))
-	if tc.workingDirectory != tc.taskRoot {
-		logger.Log("Working directory: %s", relpath(tc.workingDirectory))
-	}
-	logger.Log("URL: %s", cfg.client.TaskURL(tc.task.Slug))
-	logger.Log("")
-
 	interpolationMode := task.InterpolationMode
 	if interpolationMode != "jst" {
 		if cfg.upgradeInterpolation {
@@ -141,7 +184,7 @@ func deploySingleTaskFromTaskDefn(ctx context.Context, cfg config, tc taskConfig
 More information: https://apn.sh/jst-upgrade`)
 			interpolationMode = "jst"
 			if err := tc.def.UpgradeJST(); err != nil {
-				return err
+				return "", err
 			}
 		} else {
 			logger.Warning(`Tasks are migrating from handlebars to Airplane JS Templates! Your task has not
@@ -159,26 +202,28 @@ More information: https://apn.sh/jst-upgrade`)
 		logger.Debug("failed to gather git metadata: %v", err)
 		analytics.ReportError(errors.Wrap(err, "failed to gather git metadata"))
 	}
-	gitMeta.User = conf.GetGitUser()
-	gitMeta.Repository = conf.GetGitRepo()
+	gitMeta.User = cfg.root.Getenv("AP_GIT_USER")
+	gitMeta.Repository = cfg.root.Getenv("AP_GIT_REPO")
 
 	var image *string
 	var buildID string
 	kind, _, err := tc.def.GetKindAndOptions()
 	if err != nil {
-		return err
+		return "", err
 	}
 	if ok, err := libBuild.NeedsBuilding(kind); err != nil {
-		return err
+		return "", err
 	} else if ok {
-		resp, err := build.Run(ctx, build.NewDeployer(), build.Request{
-			Local:   cfg.local,
-			Client:  client,
-			TaskID:  task.ID,
-			Root:    tc.taskRoot,
-			Def:     tc.def,
-			Shim:    true,
-			GitMeta: gitMeta,
+		resp, err := build.Run(ctx, d.deployer, build.Request{
+			Local:      cfg.local,
+			Client:     client,
+			TaskID:     task.ID,
+			Root:       tc.taskRoot,
+			Def:        tc.def,
+			Shim:       true,
+			GitMeta:    gitMeta,
+			NoCache:    cfg.noCache,
+			ReuseBuild: cfg.reuseBuilds,
 		})
 		props.buildLocal = cfg.local
 		if resp != nil {
@@ -186,50 +231,21 @@ More information: https://apn.sh/jst-upgrade`)
 			buildID = resp.BuildID
 		}
 		if err != nil {
-			return err
+			return buildID, err
 		}
 		image = &resp.ImageURL
 	}
 
 	updateTaskRequest, err := tc.def.GetUpdateTaskRequest(ctx, client, image)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	updateTaskRequest.BuildID = pointers.String(buildID)
 	updateTaskRequest.InterpolationMode = interpolationMode
 
 	if _, err = client.UpdateTask(ctx, updateTaskRequest); err != nil {
-		return errors.Wrapf(err, "updating task %s", tc.def.GetSlug())
+		return buildID, errors.Wrapf(err, "updating task %s", tc.def.GetSlug())
 	}
-	return nil
-}
-
-func getTaskConfigFromDefn(ctx context.Context, client api.Client, def definitions.Definition_0_3, task api.Task, root string) (taskConfig, error) {
-	utr, err := def.GetUpdateTaskRequest(ctx, &client, nil)
-	if err != nil {
-		return taskConfig{}, err
-	}
-
-	taskFilePath := ""
-	entrypoint, err := def.Entrypoint()
-	if err == definitions.ErrNoEntrypoint {
-		// nothing
-	} else if err != nil {
-		return taskConfig{}, err
-	} else {
-		taskFilePath, err = filepath.Abs(entrypoint)
-		if err != nil {
-			return taskConfig{}, err
-		}
-	}
-
-	return taskConfig{
-		taskRoot:     root,
-		taskFilePath: taskFilePath,
-		task:         task,
-		def:          &def,
-		kind:         utr.Kind,
-		kindOptions:  utr.KindOptions,
-	}, nil
+	return buildID, nil
 }
@@ -2,6 +2,7 @@ package deploy
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
 
 	"github.com/MakeNowJust/heredoc"
@@ -17,6 +18,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// defaultParallelism is the default maximum number
+// of tasks that are deployed at the same time.
+const defaultParallelism = 10
+
 type config struct {
 	root         *cli.Config
 	client       *api.Client
@@ -26,6 +31,16 @@ type config struct {
 
 	upgradeInterpolation bool
 
+	dryRun   bool
+	exitCode bool
+
+	parallelism int
+	failFast    bool
+	keepGoing   bool
+
+	noCache     bool
+	reuseBuilds bool
+
 	dev       bool
 	assumeYes bool
 	assumeNo  bool
@@ -47,6 +62,8 @@ func New(c *cli.Config) *cobra.Command {
 			airplane tasks deploy ./my-task.yml
 			airplane tasks deploy my-directory
 			airplane tasks deploy ./my-task1.yml ./my-task2.yml
+			airplane tasks deploy --dry-run --exit-code my-directory
+			airplane tasks deploy --parallelism 4 --fail-fast my-directory
 		`),
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -64,6 +81,13 @@ func New(c *cli.Config) *cobra.Command {
 
 	cmd.Flags().BoolVarP(&cfg.local, "local", "L", false, "use a local Docker daemon (instead of an Airplane-hosted builder)")
 	cmd.Flags().BoolVar(&cfg.upgradeInterpolation, "jst", false, "Upgrade interpolation to JST")
+	cmd.Flags().BoolVar(&cfg.dryRun, "dry-run", false, "Print the changes deploying would make to each task, without building or updating them")
+	cmd.Flags().BoolVar(&cfg.exitCode, "exit-code", false, fmt.Sprintf("With --dry-run, exit with status %d when any task would change", exitCodeChanges))
+	cmd.Flags().IntVar(&cfg.parallelism, "parallelism", defaultParallelism, "Maximum number of tasks to deploy at the same time")
+	cmd.Flags().BoolVar(&cfg.failFast, "fail-fast", false, "Stop deploying as soon as a task fails to deploy")
+	cmd.Flags().BoolVar(&cfg.keepGoing, "keep-going", false, "Keep deploying the other tasks when a task fails to deploy (default)")
+	cmd.Flags().BoolVar(&cfg.noCache, "no-cache", false, "Always upload and build tasks, even if they are unchanged since the last deploy")
+	cmd.Flags().BoolVar(&cfg.reuseBuilds, "reuse-builds", false, "Skip building tasks that were already built from the same files and options")
 	cmd.Flags().Var(&cfg.changedFiles, "changed-files", "A file with a list of file paths that were changed, one path per line. Only tasks with changed files will be deployed")
 	// Remove dev flag + unhide these flags before release!
 	cmd.Flags().BoolVar(&cfg.dev, "dev", false, "Dev mode: warning, not guaranteed to work and subject to change.")
@@ -101,15 +125,24 @@ func run(ctx context.Context, cfg config) error {
 	if cfg.assumeYes && cfg.assumeNo {
 		return errors.New("Cannot specify both --yes and --no")
 	}
-
-	if cfg.dev && definitions.IsTaskDef(cfg.paths[0]) {
-		return deployFromTaskDefn(ctx, cfg)
+	if cfg.exitCode && !cfg.dryRun {
+		return errors.New("--exit-code can only be used with --dry-run")
+	}
+	if cfg.failFast && cfg.keepGoing {
+		return errors.New("Cannot specify both --fail-fast and --keep-going")
+	}
+	if cfg.noCache && cfg.reuseBuilds {
+		return errors.New("Cannot specify both --no-cache and --reuse-builds")
+	}
+	if cfg.parallelism < 1 {
+		return errors.New("--parallelism must be at least 1")
 	}
 
 	ext := filepath.Ext(cfg.paths[0])
-	if ext == ".yml" || ext == ".yaml" {
+	isTaskDefn := cfg.dev && definitions.IsTaskDef(cfg.paths[0])
+	if (ext == ".yml" || ext == ".yaml") && !isTaskDefn {
 		return deployFromYaml(ctx, cfg)
 	}
 
-	return NewDeployer().deployFromScript(ctx, cfg)
+	return NewDeployer().deployTasks(ctx, cfg)
 }
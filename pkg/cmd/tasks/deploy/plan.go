@@ -0,0 +1,210 @@
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// exitCodeChanges is the exit code of a dry-run with --exit-code
+// when deploying would change at least one task.
+const exitCodeChanges = 2
+
+// taskPlan describes the changes deploying a task would make.
+type taskPlan struct {
+	Slug    string        `json:"slug" yaml:"slug"`
+	Create  bool          `json:"create" yaml:"create"`
+	Changes []fieldChange `json:"changes" yaml:"changes"`
+}
+
+// fieldChange is a task field that would change on deploy.
+type fieldChange struct {
+	Field  string      `json:"field" yaml:"field"`
+	Before interface{} `json:"before" yaml:"before"`
+	After  interface{} `json:"after" yaml:"after"`
+}
+
+// planTask returns the changes that updating task with req would make.
+//
+// When the task does not exist yet, task should be its zero value and
+// exists false. The image and build are not compared since a deploy
+// always produces new ones.
+func planTask(task api.Task, exists bool, req api.UpdateTaskRequest) (taskPlan, error) {
+	plan := taskPlan{
+		Slug:    req.Slug,
+		Create:  !exists,
+		Changes: []fieldChange{},
+	}
+
+	fields := []struct {
+		name          string
+		before, after interface{}
+	}{
+		{"name", task.Name, req.Name},
+		{"description", task.Description, req.Description},
+		{"kind", task.Kind, req.Kind},
+		{"kindOptions", task.KindOptions, req.KindOptions},
+		{"command", task.Command, req.Command},
+		{"arguments", task.Arguments, req.Arguments},
+		// Parameters are wrapped in an object when marshalled, compare the slices instead.
+		{"parameters", []api.Parameter(task.Parameters), []api.Parameter(req.Parameters)},
+		{"env", task.Env, req.Env},
+		{"constraints", task.Constraints, req.Constraints},
+		{"resourceRequests", task.ResourceRequests, req.ResourceRequests},
+		{"resources", task.Resources, req.Resources},
+		{"repo", task.Repo, req.Repo},
+		{"timeout", task.Timeout, req.Timeout},
+		{"requireExplicitPermissions", task.RequireExplicitPermissions, req.RequireExplicitPermissions},
+		{"permissions", task.Permissions, req.Permissions},
+	}
+
+	for _, f := range fields {
+		before, err := normalize(f.before)
+		if err != nil {
+			return taskPlan{}, errors.Wrapf(err, "comparing %s", f.name)
+		}
+		after, err := normalize(f.after)
+		if err != nil {
+			return taskPlan{}, errors.Wrapf(err, "comparing %s", f.name)
+		}
+
+		if !reflect.DeepEqual(before, after) {
+			plan.Changes = append(plan.Changes, fieldChange{
+				Field:  f.name,
+				Before: before,
+				After:  after,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// normalize returns the JSON representation of v, so that values of the task and
+// of the update request can be compared.
+//
+// Empty lists and objects are normalized to nil, since the API does not tell them
+// apart from unset ones. Explicit zero values, such as a timeout of 0 or an empty
+// string, are kept so that changing a value to or from zero shows up as a change.
+func normalize(v interface{}) (interface{}, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret interface{}
+	if err := json.Unmarshal(buf, &ret); err != nil {
+		return nil, err
+	}
+
+	switch t := ret.(type) {
+	case []interface{}:
+		if len(t) == 0 {
+			return nil, nil
+		}
+	case map[string]interface{}:
+		if len(t) == 0 {
+			return nil, nil
+		}
+	}
+	return ret, nil
+}
+
+// printPlans prints the given plans, and returns an exit code error
+// if exitCode is set and any task would change.
+func printPlans(plans []taskPlan, exitCode bool) error {
+	print.Print(plans, func() {
+		for _, plan := range plans {
+			printPlan(plan)
+		}
+	})
+
+	var changed int
+	for _, plan := range plans {
+		if plan.Create || len(plan.Changes) > 0 {
+			changed++
+		}
+	}
+	logger.Log("%d of %d task(s) would change.", changed, len(plans))
+
+	if exitCode && changed > 0 {
+		return utils.ExitCodeError{Code: exitCodeChanges}
+	}
+	return nil
+}
+
+// printPlan prints a single plan as a field-by-field diff.
+func printPlan(plan taskPlan) {
+	switch {
+	case plan.Create:
+		logger.Log("%s %s", logger.Bold(plan.Slug), logger.Green("(create)"))
+	case len(plan.Changes) == 0:
+		logger.Log("%s %s", logger.Bold(plan.Slug), logger.Gray("(no changes)"))
+		logger.Log("")
+		return
+	default:
+		logger.Log("%s %s", logger.Bold(plan.Slug), logger.Yellow("(update)"))
+	}
+
+	for _, c := range plan.Changes {
+		if isScalar(c.Before) && isScalar(c.After) {
+			logger.Log("  %s: %s → %s", c.Field, formatScalar(c.Before), formatScalar(c.After))
+			continue
+		}
+
+		logger.Log("  %s:", c.Field)
+		before, err := yaml.Marshal(c.Before)
+		if err != nil {
+			logger.Debug("marshalling %s: %s", c.Field, err)
+			continue
+		}
+		after, err := yaml.Marshal(c.After)
+		if err != nil {
+			logger.Debug("marshalling %s: %s", c.Field, err)
+			continue
+		}
+		if c.Before == nil {
+			before = nil
+		}
+		if c.After == nil {
+			after = nil
+		}
+		diff, err := utils.Diff(strings.TrimSuffix(string(before), "\n"), strings.TrimSuffix(string(after), "\n"), "current", "deploy")
+		if err != nil {
+			logger.Debug("diffing %s: %s", c.Field, err)
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(utils.ColorDiff(diff), "\n"), "\n") {
+			logger.Log("    %s", line)
+		}
+	}
+	logger.Log("")
+}
+
+func isScalar(v interface{}) bool {
+	switch v.(type) {
+	case []interface{}, map[string]interface{}:
+		return false
+	default:
+		return true
+	}
+}
+
+func formatScalar(v interface{}) string {
+	if v == nil {
+		return logger.Gray("<empty>")
+	}
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(buf)
+}
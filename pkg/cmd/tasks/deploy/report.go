@@ -0,0 +1,79 @@
+package deploy
+
+import (
+	"time"
+
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/pkg/errors"
+)
+
+// deployStatus is the outcome of deploying a single task.
+type deployStatus string
+
+const (
+	deployStatusSucceeded deployStatus = "succeeded"
+	deployStatusFailed    deployStatus = "failed"
+	// deployStatusCanceled is the status of a deploy that was in
+	// progress when another one failed with --fail-fast.
+	deployStatusCanceled deployStatus = "canceled"
+	// deployStatusSkipped is the status of a task that was not deployed.
+	deployStatusSkipped deployStatus = "skipped"
+)
+
+// deployResult is the result of deploying a single task.
+type deployResult struct {
+	Slug            string       `json:"slug" yaml:"slug"`
+	Status          deployStatus `json:"status" yaml:"status"`
+	BuildID         string       `json:"buildID,omitempty" yaml:"buildID,omitempty"`
+	DurationSeconds float64      `json:"durationSeconds" yaml:"durationSeconds"`
+	Error           string       `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// printReport prints the results of a deploy, and returns an
+// error if any of the tasks was not deployed successfully.
+func printReport(cfg config, results []deployResult) error {
+	print.Print(results, func() {
+		for _, status := range []deployStatus{deployStatusFailed, deployStatusCanceled, deployStatusSkipped, deployStatusSucceeded} {
+			for _, res := range results {
+				if res.Status == status {
+					printResult(cfg, res)
+				}
+			}
+		}
+	})
+
+	var failed int
+	for _, res := range results {
+		if res.Status == deployStatusFailed || res.Status == deployStatusCanceled {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("%d of %d task(s) failed to deploy", failed, len(results))
+	}
+	return nil
+}
+
+func printResult(cfg config, res deployResult) {
+	duration := time.Duration(res.DurationSeconds * float64(time.Second)).Round(time.Second)
+
+	logger.Log("\n" + logger.Bold(res.Slug))
+	switch res.Status {
+	case deployStatusSucceeded:
+		logger.Log("Status: %s", logger.Bold(logger.Green("succeeded")))
+		logger.Log("Duration: %s", duration)
+		logger.Log("Execute the task: %s", cfg.client.TaskURL(res.Slug))
+	case deployStatusFailed:
+		logger.Log("Status: " + logger.Bold(logger.Red("failed")))
+		logger.Log("Duration: %s", duration)
+		logger.Error(res.Error)
+	case deployStatusCanceled:
+		logger.Log("Status: " + logger.Bold(logger.Yellow("canceled")))
+	case deployStatusSkipped:
+		logger.Log("Status: " + logger.Gray("skipped"))
+		if res.Error != "" {
+			logger.Log(logger.Gray(res.Error))
+		}
+	}
+}
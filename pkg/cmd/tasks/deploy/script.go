@@ -14,7 +14,6 @@ import (
 	"github.com/airplanedev/cli/pkg/analytics"
 	"github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/build"
-	"github.com/airplanedev/cli/pkg/conf"
 	"github.com/airplanedev/cli/pkg/logger"
 	"github.com/airplanedev/cli/pkg/taskdir/definitions"
 	"github.com/airplanedev/cli/pkg/utils/pointers"
@@ -22,42 +21,61 @@ import (
 	"github.com/airplanedev/lib/pkg/runtime"
 	"github.com/go-git/go-git/v5"
 	"github.com/pkg/errors"
-	"golang.org/x/sync/errgroup"
 )
 
-var ignoredDirectories = map[string]bool{
-	"node_modules": true,
-	"__pycache__":  true,
-	".git":         true,
-}
-
 type scriptDeployer struct {
 	deployer *build.Deployer
-
-	erroredTaskSlugs  map[string]error
-	deployedTaskSlugs []string
-	mu                sync.Mutex
 }
 
 func NewDeployer() *scriptDeployer {
 	return &scriptDeployer{
-		deployer:         build.NewDeployer(),
-		erroredTaskSlugs: make(map[string]error),
+		deployer: build.NewDeployer(),
 	}
 }
 
-// deployFromScript deploys N tasks from the given set of files or directories.
-func (d *scriptDeployer) deployFromScript(ctx context.Context, cfg config) error {
+// deployTasks deploys N tasks from the given set of files or directories.
+//
+// Task definition files are only deployed in dev mode, a script linked to
+// the same task as a definition is skipped.
+func (d *scriptDeployer) deployTasks(ctx context.Context, cfg config) error {
 	loader := logger.NewLoader(logger.LoaderOpts{HideLoader: logger.EnableDebug})
 	loader.Start()
 	scriptsToDeploy, err := d.discoverScripts(ctx, cfg.paths...)
 	if err != nil {
 		return err
 	}
+	var defnsToDeploy []string
+	if cfg.dev {
+		if defnsToDeploy, err = definitions.DiscoverTaskDefs(cfg.paths...); err != nil {
+			return err
+		}
+	}
 	loader.Stop()
 
 	var taskConfigs []taskConfig
+	// unchanged counts the definitions skipped because none of their files changed.
+	var unchanged int
+	defnSlugs := map[string]bool{}
+	for _, defn := range defnsToDeploy {
+		tc, ok, err := getTaskConfigFromDefnFile(ctx, cfg, defn)
+		if errors.Is(err, errUnchanged) {
+			unchanged++
+			continue
+		} else if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		taskConfigs = append(taskConfigs, tc)
+		defnSlugs[tc.def.GetSlug()] = true
+	}
+
 	for _, script := range scriptsToDeploy {
+		if defnSlugs[script.taskSlug] {
+			logger.Debug("Skipping %s, task %s is deployed from its definition", script.file, script.taskSlug)
+			continue
+		}
 		tc, err := getTaskConfigFromScript(ctx, *cfg.client, script)
 		if err != nil {
 			return err
@@ -77,8 +95,8 @@ func (d *scriptDeployer) deployFromScript(ctx context.Context, cfg config) error
 				filteredTaskConfigs = append(filteredTaskConfigs, tc)
 			}
 		}
-		if len(taskConfigs) != len(filteredTaskConfigs) {
-			logger.Log("Changed files specified. Filtered %d task(s) to %d affected task(s)", len(taskConfigs), len(filteredTaskConfigs))
+		if total := len(taskConfigs) + unchanged; total != len(filteredTaskConfigs) {
+			logger.Log("Changed files specified. Filtered %d task(s) to %d affected task(s)", total, len(filteredTaskConfigs))
 		}
 		taskConfigs = filteredTaskConfigs
 	}
@@ -88,6 +106,10 @@ func (d *scriptDeployer) deployFromScript(ctx context.Context, cfg config) error
 		return nil
 	}
 
+	if cfg.dryRun {
+		return planTasks(ctx, cfg, taskConfigs)
+	}
+
 	// Print out a summary before deploying.
 	noun := "task"
 	if len(taskConfigs) > 1 {
@@ -96,7 +118,7 @@ func (d *scriptDeployer) deployFromScript(ctx context.Context, cfg config) error
 	logger.Log("Deploying %v %v:\n", len(taskConfigs), noun)
 	for _, tc := range taskConfigs {
 		logger.Log(logger.Bold(tc.task.Slug))
-		logger.Log("Type: %s", tc.task.Kind)
+		logger.Log("Type: %s", tc.kind)
 		logger.Log("Root directory: %s", relpath(tc.taskRoot))
 		if tc.workingDirectory != tc.taskRoot {
 			logger.Log("Working directory: %s", relpath(tc.workingDirectory))
@@ -105,41 +127,106 @@ func (d *scriptDeployer) deployFromScript(ctx context.Context, cfg config) error
 		logger.Log("")
 	}
 
-	g := new(errgroup.Group)
-	// Concurrently deploy the tasks.
-	for _, tc := range taskConfigs {
-		tc := tc
-		g.Go(func() error {
-			err := d.deploySingleTaskFromScript(ctx, cfg, tc)
-			d.mu.Lock()
-			defer d.mu.Unlock()
-			if err != nil {
-				if !errors.As(err, &runtime.ErrNotLinked{}) {
-					d.erroredTaskSlugs[tc.task.Slug] = err
-					return err
-				}
-			} else {
-				d.deployedTaskSlugs = append(d.deployedTaskSlugs, tc.task.Slug)
+	results := d.deployAll(ctx, cfg, taskConfigs)
+	return printReport(cfg, results)
+}
+
+// deployAll concurrently deploys the given tasks, at most cfg.parallelism at a time.
+//
+// With cfg.failFast, the first failure cancels the deploys in progress and
+// the tasks that were not started yet are skipped.
+func (d *scriptDeployer) deployAll(ctx context.Context, cfg config, taskConfigs []taskConfig) []deployResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]deployResult, len(taskConfigs))
+	for i, tc := range taskConfigs {
+		results[i] = deployResult{Slug: tc.task.Slug, Status: deployStatusSkipped}
+	}
+
+	sem := make(chan struct{}, cfg.parallelism)
+	var wg sync.WaitGroup
+	for i, tc := range taskConfigs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		i, tc := i, tc
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i] = d.deployTask(ctx, cfg, tc)
+			if results[i].Status == deployStatusFailed && cfg.failFast {
+				cancel()
 			}
-			return nil
-		})
+		}()
 	}
+	wg.Wait()
 
-	groupErr := g.Wait()
+	return results
+}
 
-	// All of the deploys have finished.
-	for taskSlug, err := range d.erroredTaskSlugs {
-		logger.Log("\n" + logger.Bold(taskSlug))
-		logger.Log("Status: " + logger.Bold(logger.Red("failed")))
-		logger.Error(err.Error())
+// deployTask deploys a single task and returns its result.
+func (d *scriptDeployer) deployTask(ctx context.Context, cfg config, tc taskConfig) deployResult {
+	start := time.Now()
+	var buildID string
+	var err error
+	if tc.defnFile != "" {
+		buildID, err = d.deploySingleTaskFromTaskDefn(ctx, cfg, tc)
+	} else {
+		buildID, err = d.deploySingleTaskFromScript(ctx, cfg, tc)
+	}
+
+	res := deployResult{
+		Slug:            tc.task.Slug,
+		BuildID:         buildID,
+		DurationSeconds: time.Since(start).Seconds(),
+	}
+	switch {
+	case err == nil:
+		res.Status = deployStatusSucceeded
+	case errors.As(err, &runtime.ErrNotLinked{}):
+		res.Status = deployStatusSkipped
+	case ctx.Err() != nil:
+		res.Status = deployStatusCanceled
+	default:
+		res.Status = deployStatusFailed
 	}
-	for _, slug := range d.deployedTaskSlugs {
-		logger.Log("\n" + logger.Bold(slug))
-		logger.Log("Status: %s", logger.Bold(logger.Green("succeeded")))
-		logger.Log("Execute the task: %s", cfg.client.TaskURL(slug))
+	if err != nil {
+		res.Error = err.Error()
 	}
+	return res
+}
 
-	return groupErr
+// planTasks prints the changes deploying the given tasks would make.
+func planTasks(ctx context.Context, cfg config, taskConfigs []taskConfig) error {
+	var plans []taskPlan
+	for _, tc := range taskConfigs {
+		utr, err := tc.def.GetUpdateTaskRequest(ctx, cfg.client, nil)
+		if err != nil {
+			return err
+		}
+		if tc.defnFile == "" {
+			// Deploys from scripts keep the permissions of the task.
+			utr.RequireExplicitPermissions = tc.task.RequireExplicitPermissions
+			utr.Permissions = tc.task.Permissions
+		}
+
+		// Tasks of definitions that do not exist yet have no ID.
+		plan, err := planTask(tc.task, tc.task.ID != "", utr)
+		if err != nil {
+			return err
+		}
+		plans = append(plans, plan)
+	}
+
+	return printPlans(plans, cfg.exitCode)
 }
 
 type script struct {
@@ -151,7 +238,7 @@ type script struct {
 func (d *scriptDeployer) discoverScripts(ctx context.Context, paths ...string) ([]script, error) {
 	var scripts []script
 	for _, p := range paths {
-		if ignoredDirectories[p] {
+		if definitions.IgnoredDirectories[p] || definitions.IsTaskDef(p) {
 			continue
 		}
 		logger.Debug("Exploring file or directory: %s", p)
@@ -193,7 +280,7 @@ func (d *scriptDeployer) discoverScripts(ctx context.Context, paths ...string) (
 	return scripts, nil
 }
 
-func (d *scriptDeployer) deploySingleTaskFromScript(ctx context.Context, cfg config, tc taskConfig) (rErr error) {
+func (d *scriptDeployer) deploySingleTaskFromScript(ctx context.Context, cfg config, tc taskConfig) (_ string, rErr error) {
 	client := cfg.client
 	tp := taskDeployedProps{
 		from: "script",
@@ -227,7 +314,7 @@ func (d *scriptDeployer) deploySingleTaskFromScript(ctx context.Context, cfg con
 More information: https://apn.sh/jst-upgrade`)
 			interpolationMode = "jst"
 			if err := tc.def.UpgradeJST(); err != nil {
-				return err
+				return "", err
 			}
 		} else {
 			logger.Warning(`Tasks are migrating from handlebars to Airplane JS Templates! Your task has not
@@ -245,31 +332,33 @@ More information: https://apn.sh/jst-upgrade`)
 		logger.Debug("failed to gather git metadata: %v", err)
 		analytics.ReportError(errors.Wrap(err, "failed to gather git metadata"))
 	}
-	gitMeta.User = conf.GetGitUser()
-	gitMeta.Repository = conf.GetGitRepo()
+	gitMeta.User = cfg.root.Getenv("AP_GIT_USER")
+	gitMeta.Repository = cfg.root.Getenv("AP_GIT_REPO")
 
 	env, err := tc.def.GetEnv()
 	if err != nil {
-		return err
+		return "", err
 	}
 	resp, err := build.Run(ctx, d.deployer, build.Request{
-		Local:   cfg.local,
-		Client:  client,
-		TaskID:  task.ID,
-		Root:    tc.taskRoot,
-		Def:     tc.def,
-		TaskEnv: env,
-		Shim:    true,
-		GitMeta: gitMeta,
+		Local:      cfg.local,
+		Client:     client,
+		TaskID:     task.ID,
+		Root:       tc.taskRoot,
+		Def:        tc.def,
+		TaskEnv:    env,
+		Shim:       true,
+		GitMeta:    gitMeta,
+		NoCache:    cfg.noCache,
+		ReuseBuild: cfg.reuseBuilds,
 	})
 	if err != nil {
-		return err
+		return "", err
 	}
 	tp.buildID = resp.BuildID
 
 	utr, err := tc.def.GetUpdateTaskRequest(ctx, client, &resp.ImageURL)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	utr.BuildID = pointers.String(resp.BuildID)
@@ -278,17 +367,19 @@ More information: https://apn.sh/jst-upgrade`)
 	utr.Permissions = task.Permissions
 
 	_, err = client.UpdateTask(ctx, utr)
-	return err
+	return resp.BuildID, err
 }
 
 type taskConfig struct {
 	taskRoot         string
 	workingDirectory string
 	taskFilePath     string
-	task             api.Task
-	def              definitions.DefinitionInterface
-	kind             libBuild.TaskKind
-	kindOptions      libBuild.KindOptions
+	// defnFile is the path of the task definition, or empty when deploying from a script.
+	defnFile    string
+	task        api.Task
+	def         definitions.DefinitionInterface
+	kind        libBuild.TaskKind
+	kindOptions libBuild.KindOptions
 }
 
 // getTaskConfig a task and associated information from a script.
@@ -22,6 +22,10 @@ func deployFromYaml(ctx context.Context, cfg config) (rErr error) {
 	}
 	start := time.Now()
 	defer func() {
+		if cfg.dryRun {
+			// Nothing was deployed.
+			return
+		}
 		analytics.Track(cfg.root, "Task Deployed", map[string]interface{}{
 			"from":             props.from,
 			"kind":             props.kind,
@@ -51,9 +55,11 @@ func deployFromYaml(ctx context.Context, cfg config) (rErr error) {
 	}
 	props.taskSlug = def.Slug
 
-	err = ensureConfigsExist(ctx, client, def)
-	if err != nil {
-		return err
+	if !cfg.dryRun {
+		err = ensureConfigsExist(ctx, client, def)
+		if err != nil {
+			return err
+		}
 	}
 
 	kind, kindOptions, err := def.GetKindAndOptions()
@@ -87,6 +93,39 @@ func deployFromYaml(ctx context.Context, cfg config) (rErr error) {
 		command = def.Image.Command
 	}
 
+	if cfg.dryRun {
+		var exists = true
+		task, err := client.GetTask(ctx, def.Slug)
+		if _, ok := err.(*api.TaskMissingError); ok {
+			exists = false
+		} else if err != nil {
+			return errors.Wrap(err, "getting task")
+		}
+
+		plan, err := planTask(task, exists, api.UpdateTaskRequest{
+			Slug:                       def.Slug,
+			Name:                       def.Name,
+			Description:                def.Description,
+			Command:                    command,
+			Arguments:                  def.Arguments,
+			Parameters:                 def.Parameters,
+			Constraints:                def.Constraints,
+			Env:                        def.Env,
+			ResourceRequests:           def.ResourceRequests,
+			Resources:                  resources,
+			Kind:                       kind,
+			KindOptions:                kindOptions,
+			Repo:                       def.Repo,
+			RequireExplicitPermissions: task.RequireExplicitPermissions,
+			Permissions:                task.Permissions,
+			Timeout:                    def.Timeout,
+		})
+		if err != nil {
+			return err
+		}
+		return printPlans([]taskPlan{plan}, cfg.exitCode)
+	}
+
 	task, err := client.GetTask(ctx, def.Slug)
 	if _, ok := err.(*api.TaskMissingError); ok {
 		// A task with this slug does not exist, so we should create one.
@@ -146,11 +185,13 @@ More information: https://apn.sh/jst-upgrade`)
 		return err
 	} else if ok {
 		resp, err := build.Run(ctx, build.NewDeployer(), build.Request{
-			Local:  cfg.local,
-			Client: client,
-			Root:   dir.DefinitionRootPath(),
-			Def:    &def,
-			TaskID: task.ID,
+			Local:      cfg.local,
+			Client:     client,
+			Root:       dir.DefinitionRootPath(),
+			Def:        &def,
+			TaskID:     task.ID,
+			NoCache:    cfg.noCache,
+			ReuseBuild: cfg.reuseBuilds,
 		})
 		props.buildLocal = cfg.local
 		if resp != nil {
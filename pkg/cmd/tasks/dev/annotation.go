@@ -0,0 +1,114 @@
+package dev
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/taskdir/definitions"
+	"github.com/airplanedev/lib/pkg/runtime"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// annotationMarker starts a task definition annotated inline in a script's comments:
+//
+//	# @airplane
+//	# name: Hello
+//	# slug: hello
+//	# parameters:
+//	#   - name: Name
+//	#     slug: name
+//	#     type: shorttext
+//
+// The comment lines that follow the marker are read as a task definition. Its kind is
+// suggested by the script's extension, and its entrypoint is the script itself.
+var annotationMarker = regexp.MustCompile(`^\s*(#|//|--)\s*@airplane\s*$`)
+
+// readAnnotatedTask reads the task annotated inline in the script at file.
+//
+// The returned bool is false when the script has no annotation.
+func readAnnotatedTask(file string) (localTask, bool, error) {
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return localTask{}, false, errors.Wrapf(err, "absolute path of %s", file)
+	}
+
+	buf, ok, err := readAnnotation(path)
+	if err != nil || !ok {
+		return localTask{}, false, err
+	}
+
+	var def map[string]interface{}
+	if err := yaml.Unmarshal(buf, &def); err != nil {
+		return localTask{}, false, errors.Wrapf(err, "reading @airplane annotation in %s", file)
+	}
+	if def == nil {
+		def = map[string]interface{}{}
+	}
+
+	kind, err := runtime.SuggestKind(filepath.Ext(path))
+	if err != nil {
+		return localTask{}, false, errors.Wrapf(err, "task kind of %s", file)
+	}
+	kindDef, _ := def[string(kind)].(map[string]interface{})
+	if kindDef == nil {
+		kindDef = map[string]interface{}{}
+	}
+	if _, ok := kindDef["entrypoint"]; !ok {
+		kindDef["entrypoint"] = filepath.Base(path)
+	}
+	def[string(kind)] = kindDef
+
+	if buf, err = json.Marshal(def); err != nil {
+		return localTask{}, false, errors.Wrapf(err, "reading @airplane annotation in %s", file)
+	}
+	var d definitions.Definition_0_3
+	if err := d.Unmarshal(definitions.TaskDefFormatJSON, buf); err != nil {
+		return localTask{}, false, errors.Wrapf(err, "reading @airplane annotation in %s", file)
+	}
+
+	lt, err := newLocalTask_0_3(d, path, filepath.Dir(path))
+	if err != nil {
+		return localTask{}, false, err
+	}
+	return lt, true, nil
+}
+
+// readAnnotation returns the comment lines that follow the @airplane marker in
+// the file at path, without their comment prefix.
+func readAnnotation(path string) ([]byte, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "opening %s", path)
+	}
+	defer f.Close()
+
+	var prefix string
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " \t")
+		if prefix == "" {
+			if m := annotationMarker.FindStringSubmatch(line); m != nil {
+				prefix = m[1]
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, prefix) {
+			break
+		}
+		// Keep the indentation after the prefix, which is part of the YAML.
+		lines = append(lines, strings.TrimPrefix(line[len(prefix):], " "))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, false, errors.Wrapf(err, "reading %s", path)
+	}
+	if prefix == "" {
+		return nil, false, nil
+	}
+	return []byte(strings.Join(lines, "\n")), true, nil
+}
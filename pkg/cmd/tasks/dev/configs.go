@@ -0,0 +1,142 @@
+package dev
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cmd/auth/login"
+	"github.com/airplanedev/cli/pkg/configs"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/lib/pkg/utils/fsx"
+	"github.com/pkg/errors"
+)
+
+// taskEnv is the environment of a task, as a deployed run would see it.
+type taskEnv struct {
+	// vars are the env vars of the task, with config
+	// variables resolved to their values.
+	vars map[string]string
+	// secrets are the values of secret config variables, which are
+	// redacted from everything the task prints.
+	secrets []string
+}
+
+// redacted is what secrets are replaced with in logs.
+const redacted = "********"
+
+// redact replaces the secrets of env in s.
+func (env taskEnv) redact(s string) string {
+	for _, secret := range env.secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return s
+}
+
+// resolveEnv resolves the env vars of task and its config variable
+// parameters in paramValues, which are updated in place.
+//
+// Config variables are read from the airplane.configs.yaml files in dirs,
+// and otherwise from the API if remote configs are enabled.
+func resolveEnv(ctx context.Context, cfg config, task api.Task, dirs []string, paramValues api.Values) (taskEnv, error) {
+	env := taskEnv{vars: map[string]string{}}
+
+	// Collect the names of all referenced config variables.
+	refs := map[string]bool{}
+	for k, v := range task.Env {
+		switch {
+		case v.Config != nil:
+			refs[*v.Config] = true
+		case v.Value != nil:
+			env.vars[k] = *v.Value
+		}
+	}
+	configParams := map[string]string{}
+	for _, p := range task.Parameters {
+		if p.Type != api.TypeConfigVar {
+			continue
+		}
+		if v, ok := paramValues[p.Slug].(map[string]interface{}); ok {
+			if name, ok := v["name"].(string); ok && name != "" {
+				configParams[p.Slug] = name
+				refs[name] = true
+			}
+		}
+	}
+	if len(refs) == 0 {
+		return env, nil
+	}
+
+	var paths []string
+	for _, dir := range dirs {
+		if fp := filepath.Join(dir, configs.LocalFile); fsx.Exists(fp) {
+			logger.Debug("Loading config variables from %s", logger.Bold(fp))
+			paths = append(paths, fp)
+		}
+	}
+	values, err := configs.ReadLocal(paths...)
+	if err != nil {
+		return taskEnv{}, err
+	}
+
+	var missing []string
+	for ref := range refs {
+		if _, ok := values[ref]; !ok {
+			missing = append(missing, ref)
+		}
+	}
+	sort.Strings(missing)
+
+	if len(missing) > 0 && cfg.remoteConfigs {
+		if err := login.EnsureLoggedIn(ctx, cfg.root); err != nil {
+			return taskEnv{}, err
+		}
+		for _, ref := range missing {
+			nt, err := configs.ParseName(ref)
+			if err != nil {
+				return taskEnv{}, errors.Errorf("invalid config name: %s - expected my_config or my_config:tag", ref)
+			}
+			logger.Debug("Fetching config variable %s", logger.Bold(ref))
+			resp, err := cfg.root.Client.GetConfig(ctx, api.GetConfigRequest{
+				Name:       nt.Name,
+				Tag:        nt.Tag,
+				ShowSecret: true,
+			})
+			if err != nil {
+				return taskEnv{}, errors.Wrapf(err, "getting config %s", ref)
+			}
+			values[ref] = resp.Config
+		}
+		missing = nil
+	}
+
+	if len(missing) > 0 {
+		return taskEnv{}, errors.Errorf(
+			"config variable(s) %s not found: add them to %s, or use --remote-configs to fetch them from Airplane",
+			strings.Join(missing, ", "), configs.LocalFile,
+		)
+	}
+
+	for k, v := range task.Env {
+		if v.Config != nil {
+			env.vars[k] = values[*v.Config].Value
+		}
+	}
+	for slug, name := range configParams {
+		paramValues[slug] = map[string]interface{}{
+			"name":  name,
+			"value": values[name].Value,
+		}
+	}
+	for ref := range refs {
+		if v := values[ref]; v.IsSecret {
+			env.secrets = append(env.secrets, v.Value)
+		}
+	}
+
+	return env, nil
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/pkg/api"
@@ -19,6 +20,7 @@ import (
 	"github.com/airplanedev/cli/pkg/params"
 	"github.com/airplanedev/cli/pkg/print"
 	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/lib/pkg/build"
 	"github.com/airplanedev/lib/pkg/outputs"
 	"github.com/airplanedev/lib/pkg/runtime"
 	"github.com/airplanedev/lib/pkg/utils/bufiox"
@@ -30,10 +32,19 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// stopTimeout is the time a task's process is given to exit after
+// being interrupted, before it is killed.
+var stopTimeout = 5 * time.Second
+
 type config struct {
-	root *cli.Config
-	file string
-	args []string
+	root          *cli.Config
+	file          string
+	args          []string
+	watch         bool
+	remoteConfigs bool
+
+	paramsFile string
+	paramsJSON string
 }
 
 func New(c *cli.Config) *cobra.Command {
@@ -42,15 +53,35 @@ func New(c *cli.Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "dev ./path/to/file",
 		Short: "Locally run a task",
-		Long:  "Locally runs a task, optionally with specific parameters.",
+		Long: heredoc.Doc(`
+			Locally runs a task, optionally with specific parameters.
+
+			The task is read from a local task definition (a *.task.yaml or airplane.yml file)
+			that describes the script, or from a definition annotated in the script's comments
+			after an @airplane line, in which case no login or network access is required.
+			Otherwise the task is fetched from Airplane using the slug linked in the script.
+
+			Config variables referenced by the task's env vars and parameters are read from
+			airplane.configs.yaml files between the task's root and its entrypoint, which map
+			names (optionally with a tag, as in name:tag) to values. With --remote-configs,
+			config variables that are not defined locally are fetched from Airplane.
+
+			With --output json, log lines, status changes and outputs are printed to stdout
+			as newline-delimited JSON events, such as {"type":"log","ts":...,"text":...}.
+
+			Parameter values can also be read from a JSON or YAML file with --params-file,
+			or as JSON with --params-json, which reads stdin if it is "-". Parameters passed
+			as flags after -- override them.
+		`),
 		Example: heredoc.Doc(`
 			airplane dev ./task.js [-- <parameters...>]
 			airplane dev ./task.ts [-- <parameters...>]
+			airplane dev ./my_task.task.yaml [-- <parameters...>]
+			airplane dev --watch ./task.ts [-- <parameters...>]
+			airplane dev ./task.ts --params-file params.yaml [-- <parameters...>]
 		`),
-		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
-			// TODO: update the `dev` command to work w/out internet access
-			return login.EnsureLoggedIn(cmd.Root().Context(), c)
-		}),
+		// Tasks fetched from Airplane log in when they are fetched.
+		PersistentPreRunE: utils.RootPersistentPreRunE,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 {
 				return errors.New(`expected a file: airplane dev ./path/to/file`)
@@ -62,6 +93,11 @@ func New(c *cli.Config) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVarP(&cfg.watch, "watch", "w", false, "Re-run the task whenever a file in its root directory changes")
+	cmd.Flags().BoolVar(&cfg.remoteConfigs, "remote-configs", false, "Fetch config variables that are not defined in airplane.configs.yaml from Airplane")
+	cmd.Flags().StringVar(&cfg.paramsFile, "params-file", "", "JSON or YAML file of parameter values, keyed by slug")
+	cmd.Flags().StringVar(&cfg.paramsJSON, "params-json", "", `JSON object of parameter values, keyed by slug, or "-" to read it from stdin`)
+
 	return cmd
 }
 
@@ -70,14 +106,21 @@ func run(ctx context.Context, cfg config) error {
 		return errors.Errorf("Unable to open file: %s", cfg.file)
 	}
 
-	slug, err := slugFromScript(cfg.file)
+	var task api.Task
+	var taskSource string
+	lt, ok, err := findLocalTask(cfg.file)
 	if err != nil {
 		return err
-	}
-
-	task, err := cfg.root.Client.GetTask(ctx, slug)
-	if err != nil {
-		return errors.Wrap(err, "getting task")
+	} else if ok {
+		logger.Debug("Using task definition %s", lt.defPath)
+		task = lt.task
+		taskSource = relpath(lt.defPath)
+		cfg.file = lt.entrypoint
+	} else {
+		if task, err = getRemoteTask(ctx, cfg); err != nil {
+			return err
+		}
+		taskSource = cfg.root.Client.TaskURL(task.Slug)
 	}
 
 	r, err := runtime.Lookup(cfg.file, task.Kind)
@@ -85,59 +128,115 @@ func run(ctx context.Context, cfg config) error {
 		return errors.Wrapf(err, "unsupported file type: %s", filepath.Base(cfg.file))
 	}
 
-	paramValues, err := params.CLI(cfg.args, cfg.root.Client, task)
+	values, err := params.ReadValues(cfg.paramsFile, cfg.paramsJSON, task)
+	if err != nil {
+		return err
+	}
+	paramValues, err := params.CLI(cfg.args, task, values, params.LocalUpload)
 	if errors.Is(err, flag.ErrHelp) {
 		return nil
 	} else if err != nil {
 		return err
 	}
 
-	logger.Log("Locally running %s task %s", logger.Bold(task.Name), logger.Gray("("+cfg.root.Client.TaskURL(task.Slug)+")"))
-	logger.Log("")
-
 	path, err := filepath.Abs(cfg.file)
 	if err != nil {
 		return errors.Wrapf(err, "absolute path of %s", cfg.file)
 	}
 
+	dirs, err := devDirs(r, path)
+	if err != nil {
+		return err
+	}
+	env, err := resolveEnv(ctx, cfg, task, dirs, paramValues)
+	if err != nil {
+		return err
+	}
+
+	logger.Log("Locally running %s task %s", logger.Bold(task.Name), logger.Gray("("+taskSource+")"))
+	logger.Log("")
+
+	if cfg.watch {
+		return watch(ctx, r, path, task.KindOptions, paramValues, env)
+	}
+
+	outputs, err := runTask(ctx, r, path, task.KindOptions, paramValues, env)
+	if err != nil {
+		return err
+	}
+
+	if print.Events() {
+		print.OutputsEvent("", outputs)
+	} else {
+		print.Outputs(outputs)
+	}
+
+	return nil
+}
+
+// runTask runs the task at path once with the given env and returns its outputs.
+//
+// Once ctx is canceled the task's process is asked to stop, and killed
+// if it does not stop within stopTimeout.
+func runTask(ctx context.Context, r runtime.Interface, path string, kindOptions build.KindOptions, paramValues api.Values, taskEnv taskEnv) (_ api.Outputs, rerr error) {
+	print.PrintEvent(print.Event{Type: print.EventStatus, Status: api.RunActive})
+	defer func() {
+		e := print.Event{Type: print.EventStatus, Status: api.RunSucceeded}
+		if rerr != nil {
+			e.Status, e.Error = api.RunFailed, taskEnv.redact(rerr.Error())
+			if ctx.Err() != nil {
+				e.Status = api.RunCancelled
+			}
+		}
+		print.PrintEvent(e)
+	}()
+
 	cmds, closer, err := r.PrepareRun(ctx, &logger.StdErrLogger{}, runtime.PrepareRunOptions{
 		Path:        path,
 		ParamValues: paramValues,
-		KindOptions: task.KindOptions,
+		KindOptions: kindOptions,
 	})
 	if err != nil {
-		return err
+		return api.Outputs{}, err
 	}
 	if closer != nil {
 		defer closer.Close()
 	}
 
-	cmd := exec.CommandContext(ctx, cmds[0], cmds[1:]...)
-	logger.Debug("Running %s", logger.Bold(strings.Join(cmd.Args, " ")))
+	cmd := exec.Command(cmds[0], cmds[1:]...)
+	logger.Debug("Running %s", logger.Bold(taskEnv.redact(strings.Join(cmd.Args, " "))))
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return errors.Wrap(err, "stdout")
+		return api.Outputs{}, errors.Wrap(err, "stdout")
 	}
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
-		return errors.Wrap(err, "stderr")
+		return api.Outputs{}, errors.Wrap(err, "stderr")
 	}
 
 	// Load environment variables from .env files:
 	env, err := getDevEnv(r, path)
 	if err != nil {
-		return err
+		return api.Outputs{}, err
 	}
 	// cmd.Env defaults to os.Environ _only if empty_. Since we add
 	// to it, we need to also set it to os.Environ.
+	//
+	// Env vars of the task come first so that .env files can override them.
 	cmd.Env = os.Environ()
+	for k, v := range taskEnv.vars {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
 	for k, v := range env {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
 	}
 
+	setProcessGroup(cmd)
 	if err := cmd.Start(); err != nil {
-		return errors.Wrap(err, "starting")
+		return api.Outputs{}, errors.Wrap(err, "starting")
 	}
+	stopped := stopOnDone(ctx, cmd)
+	defer stopped()
 
 	// mu guards o and chunks
 	var mu sync.Mutex
@@ -166,7 +265,11 @@ func run(ctx context.Context, cfg config) error {
 				mu.Unlock()
 			}
 
-			logger.Log("[%s] %s", logger.Gray("log"), line)
+			if print.Events() {
+				print.PrintEvent(print.Event{Type: print.EventLog, Level: api.LogLevelInfo, Text: taskEnv.redact(line)})
+			} else {
+				logger.Log("[%s] %s", logger.Gray("log"), taskEnv.redact(line))
+			}
 		}
 		return errors.Wrap(scanner.Err(), "scanning logs")
 	}
@@ -179,16 +282,52 @@ func run(ctx context.Context, cfg config) error {
 		return logParser(stderr)
 	})
 	if err := eg.Wait(); err != nil {
-		return err
+		return api.Outputs{}, err
 	}
 
 	if err := cmd.Wait(); err != nil {
-		return errors.Wrap(err, "waiting")
+		if ctx.Err() != nil {
+			return api.Outputs{}, ctx.Err()
+		}
+		return api.Outputs{}, errors.Wrap(err, "waiting")
 	}
 
-	print.Outputs(api.Outputs(o))
+	return api.Outputs(o), nil
+}
 
-	return nil
+// stopOnDone stops the process of cmd, along with the processes it started,
+// once ctx is canceled, first by interrupting them, then by killing them after
+// stopTimeout.
+//
+// The returned function must be called once the process exited.
+func stopOnDone(ctx context.Context, cmd *exec.Cmd) func() {
+	exited := make(chan struct{})
+
+	go func() {
+		select {
+		case <-exited:
+			return
+		case <-ctx.Done():
+		}
+
+		if err := interruptProcess(cmd); err != nil {
+			// Interrupts are not supported on all platforms.
+			logger.Debug("interrupting process: %s", err)
+			killProcess(cmd)
+			return
+		}
+
+		select {
+		case <-exited:
+		case <-time.After(stopTimeout):
+			logger.Debug("process did not stop after %s, killing it", stopTimeout)
+			killProcess(cmd)
+		}
+	}()
+
+	return func() {
+		close(exited)
+	}
 }
 
 // getDevEnv will return a map of env vars, loading from .env and airplane.env
@@ -198,7 +337,7 @@ func run(ctx context.Context, cfg config) error {
 // and entrypoint dir (inclusive). A second pass is done to look for airplane.env
 // files. Env vars from successive files are merged in and overwrite duplicate keys.
 func getDevEnv(r runtime.Interface, path string) (map[string]string, error) {
-	root, err := r.Root(path)
+	dirs, err := devDirs(r, path)
 	if err != nil {
 		return nil, err
 	}
@@ -209,13 +348,6 @@ func getDevEnv(r runtime.Interface, path string) (map[string]string, error) {
 	// from earlier .env files.
 	dotenvs := []string{}
 
-	// Loop through directories from [workdir, root] inclusive, in reverse
-	// order.
-	dirs := []string{}
-	for dir := filepath.Dir(path); dir != filepath.Dir(root); dir = filepath.Dir(dir) {
-		dirs = append([]string{dir}, dirs...)
-	}
-
 	for _, file := range []string{".env", "airplane.env"} {
 		for _, dir := range dirs {
 			fp := filepath.Join(dir, file)
@@ -234,6 +366,53 @@ func getDevEnv(r runtime.Interface, path string) (map[string]string, error) {
 	return env, errors.Wrap(err, "reading .env")
 }
 
+// devDirs returns the directories from the root of the task at path to
+// its entrypoint's directory, inclusive, in that order.
+func devDirs(r runtime.Interface, path string) ([]string, error) {
+	root, err := r.Root(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := []string{}
+	for dir := filepath.Dir(path); dir != filepath.Dir(root); dir = filepath.Dir(dir) {
+		dirs = append([]string{dir}, dirs...)
+	}
+	return dirs, nil
+}
+
+// getRemoteTask fetches the task linked in the script from the API.
+func getRemoteTask(ctx context.Context, cfg config) (api.Task, error) {
+	slug, err := slugFromScript(cfg.file)
+	if err != nil {
+		return api.Task{}, err
+	}
+
+	if err := login.EnsureLoggedIn(ctx, cfg.root); err != nil {
+		return api.Task{}, err
+	}
+
+	task, err := cfg.root.Client.GetTask(ctx, slug)
+	if err != nil {
+		return api.Task{}, errors.Wrap(err, "getting task")
+	}
+	return task, nil
+}
+
+// relpath returns path relative to the working directory, or path
+// itself if it cannot be made relative.
+func relpath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
 // slugFromScript attempts to extract a slug from a file based on its contents.
 func slugFromScript(file string) (string, error) {
 	slug, ok := runtime.Slug(file)
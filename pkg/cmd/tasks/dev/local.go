@@ -0,0 +1,233 @@
+package dev
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/taskdir"
+	"github.com/airplanedev/cli/pkg/taskdir/definitions"
+	"github.com/airplanedev/lib/pkg/runtime"
+	"github.com/airplanedev/lib/pkg/utils/fsx"
+	"github.com/pkg/errors"
+)
+
+// localTask is a task resolved from a task definition on disk.
+type localTask struct {
+	task api.Task
+	// defPath is the absolute path of the task definition.
+	defPath string
+	// entrypoint is the absolute path of the script to run, empty
+	// when the task kind has no entrypoint.
+	entrypoint string
+}
+
+// isDefinitionFile returns true if file is a task definition, either a
+// *.task.{yaml,yml,json} file as discovered by deploy, or an airplane.yml file.
+func isDefinitionFile(file string) bool {
+	return definitions.IsTaskDef(file) || filepath.Base(file) == "airplane.yml"
+}
+
+// findLocalTask resolves the task of the given file from a local task definition.
+//
+// If file is a task definition it is read directly, and if it is a script annotated
+// with @airplane its annotation is read. Otherwise the directory of file and its
+// parents, up to the root of the task or the enclosing git repository, are searched
+// for a definition whose entrypoint is file or whose slug matches the slug linked in
+// the script.
+//
+// The returned bool is false when no local definition describes file.
+func findLocalTask(file string) (localTask, bool, error) {
+	if isDefinitionFile(file) {
+		lt, err := readLocalTask(file)
+		if err != nil {
+			return localTask{}, false, err
+		}
+		if lt.entrypoint == "" {
+			return localTask{}, false, errors.Errorf("task %s has no entrypoint to run locally", lt.task.Slug)
+		}
+		return lt, true, nil
+	}
+
+	if lt, ok, err := readAnnotatedTask(file); err != nil || ok {
+		return lt, ok, err
+	}
+
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return localTask{}, false, errors.Wrapf(err, "absolute path of %s", file)
+	}
+	slug, _ := runtime.Slug(file)
+	root := taskRoot(path)
+
+	var bySlug *localTask
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		defPaths, err := listDefinitions(dir)
+		if err != nil {
+			return localTask{}, false, err
+		}
+
+		for _, defPath := range defPaths {
+			lt, err := readLocalTask(defPath)
+			if err != nil {
+				// Unrelated definitions may be invalid, they should not prevent
+				// running this task.
+				logger.Debug("Skipping %s: %s", defPath, err)
+				continue
+			}
+
+			if lt.entrypoint == path {
+				return lt, true, nil
+			}
+			if slug != "" && lt.task.Slug == slug && bySlug == nil {
+				bySlug = &lt
+			}
+		}
+
+		if dir == root || fsx.Exists(filepath.Join(dir, ".git")) || filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	if bySlug != nil {
+		return *bySlug, true, nil
+	}
+	return localTask{}, false, nil
+}
+
+// taskRoot returns the root of the task at path according to its runtime, or the
+// directory of path if it has no known runtime.
+func taskRoot(path string) string {
+	dir := filepath.Dir(path)
+	kind, err := runtime.SuggestKind(filepath.Ext(path))
+	if err != nil {
+		return dir
+	}
+	r, err := runtime.Lookup(path, kind)
+	if err != nil {
+		return dir
+	}
+	root, err := r.Root(path)
+	if err != nil || root == "" {
+		return dir
+	}
+	return root
+}
+
+// listDefinitions returns the paths of the task definitions in dir.
+func listDefinitions(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading directory %s", dir)
+	}
+
+	var defPaths []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if name := entry.Name(); isDefinitionFile(name) {
+			defPaths = append(defPaths, filepath.Join(dir, name))
+		}
+	}
+	return defPaths, nil
+}
+
+// readLocalTask reads the task described by the definition at defPath.
+func readLocalTask(defPath string) (localTask, error) {
+	if definitions.IsTaskDef(defPath) {
+		return readLocalTask_0_3(defPath)
+	}
+
+	dir, err := taskdir.Open(defPath, false)
+	if err != nil {
+		return localTask{}, err
+	}
+	defer dir.Close()
+
+	def, err := dir.ReadDefinition()
+	if err != nil {
+		return localTask{}, err
+	}
+
+	kind, kindOptions, err := def.GetKindAndOptions()
+	if err != nil {
+		return localTask{}, err
+	}
+
+	lt := localTask{
+		task: api.Task{
+			Name:        def.Name,
+			Slug:        def.Slug,
+			Parameters:  def.Parameters,
+			Kind:        kind,
+			KindOptions: kindOptions,
+			Env:         def.Env,
+		},
+		defPath: dir.DefinitionPath(),
+	}
+	if ep, ok := kindOptions["entrypoint"].(string); ok && ep != "" {
+		lt.entrypoint = filepath.Join(dir.DefinitionRootPath(), ep)
+	}
+
+	return lt, nil
+}
+
+// readLocalTask_0_3 reads the task described by a *.task.{yaml,yml,json} definition.
+func readLocalTask_0_3(defPath string) (localTask, error) {
+	dir, err := taskdir.Open(defPath, true)
+	if err != nil {
+		return localTask{}, err
+	}
+	defer dir.Close()
+
+	def, err := dir.ReadDefinition_0_3()
+	if err != nil {
+		return localTask{}, err
+	}
+
+	return newLocalTask_0_3(def, dir.DefinitionPath(), dir.DefinitionRootPath())
+}
+
+// newLocalTask_0_3 returns the task described by def, which is read from defPath
+// and whose entrypoint is relative to root.
+func newLocalTask_0_3(def definitions.Definition_0_3, defPath, root string) (localTask, error) {
+	kind, kindOptions, err := def.GetKindAndOptions()
+	if err != nil {
+		return localTask{}, err
+	}
+
+	parameters, err := def.GetParameters()
+	if err != nil {
+		return localTask{}, err
+	}
+
+	env, err := def.GetEnv()
+	if err != nil {
+		return localTask{}, err
+	}
+
+	lt := localTask{
+		task: api.Task{
+			Name:        def.Name,
+			Slug:        def.Slug,
+			Parameters:  parameters,
+			Kind:        kind,
+			KindOptions: kindOptions,
+			Env:         env,
+		},
+		defPath: defPath,
+	}
+
+	ep, err := def.Entrypoint()
+	if err == definitions.ErrNoEntrypoint {
+		// nothing
+	} else if err != nil {
+		return localTask{}, err
+	} else {
+		lt.entrypoint = filepath.Join(root, ep)
+	}
+
+	return lt, nil
+}
@@ -0,0 +1,22 @@
+//go:build windows
+// +build windows
+
+package dev
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup does nothing, since processes are stopped one at a time on Windows.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// interruptProcess interrupts the process of cmd.
+func interruptProcess(cmd *exec.Cmd) error {
+	return cmd.Process.Signal(os.Interrupt)
+}
+
+// killProcess kills the process of cmd.
+func killProcess(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,224 @@
+package dev
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/lib/pkg/build"
+	"github.com/airplanedev/lib/pkg/build/ignore"
+	"github.com/airplanedev/lib/pkg/runtime"
+	"github.com/airplanedev/ojson"
+	"github.com/pkg/errors"
+)
+
+// pollInterval is the interval at which the task's root
+// is checked for changes in watch mode.
+var pollInterval = 500 * time.Millisecond
+
+// watch runs the task at path, and runs it again with the same
+// parameter values whenever a file in its root changes.
+//
+// A run that is still in progress when a file changes is stopped first.
+func watch(ctx context.Context, r runtime.Interface, path string, kindOptions build.KindOptions, paramValues api.Values, env taskEnv) error {
+	root, err := r.Root(path)
+	if err != nil {
+		return err
+	}
+
+	include, err := ignore.Func(root)
+	if err != nil {
+		return err
+	}
+
+	snap, err := snapshot(root, include)
+	if err != nil {
+		return err
+	}
+
+	logger.Log(logger.Gray("Watching %s for changes, press Ctrl-C to stop.", relpath(root)))
+	logger.Log("")
+
+	var prevOutputs *api.Outputs
+	for {
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		var outputs api.Outputs
+		var runErr error
+		go func() {
+			defer close(done)
+			outputs, runErr = runTask(runCtx, r, path, kindOptions, paramValues, env)
+		}()
+
+		changed, err := waitForChange(ctx, root, include, snap, done)
+		if err == nil && changed == nil && ctx.Err() == nil {
+			// The run finished on its own, print its result and wait for
+			// the next change.
+			printRun(outputs, runErr, prevOutputs)
+			if runErr == nil {
+				prevOutputs = &outputs
+			}
+			changed, err = waitForChange(ctx, root, include, snap, nil)
+		}
+		cancel()
+		<-done
+		if err != nil {
+			return err
+		}
+		if changed == nil {
+			// The context was canceled.
+			return nil
+		}
+
+		snap = changed.snap
+		logger.Log("")
+		logger.Log(logger.Gray("──── %s changed, re-running ────", changed.file))
+		logger.Log("")
+	}
+}
+
+// printRun prints the result of a run in watch mode, along with
+// a diff of its outputs against the previous successful run.
+func printRun(outputs api.Outputs, err error, prev *api.Outputs) {
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+
+	if print.Events() {
+		print.OutputsEvent("", outputs)
+		return
+	}
+
+	print.Outputs(outputs)
+	if prev == nil {
+		return
+	}
+
+	diff, err := outputsDiff(*prev, outputs)
+	if err != nil {
+		logger.Debug("diffing outputs: %s", err)
+		return
+	}
+	if diff == "" {
+		logger.Log(logger.Gray("Outputs are unchanged since the previous run."))
+		return
+	}
+	logger.Log(logger.Gray("Outputs changed since the previous run:"))
+	logger.Log(utils.ColorDiff(strings.TrimSuffix(diff, "\n")))
+}
+
+// outputsDiff returns a diff of the JSON representation of two outputs.
+func outputsDiff(a, b api.Outputs) (string, error) {
+	aj, err := json.MarshalIndent(ojson.Value(a), "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling outputs")
+	}
+	bj, err := json.MarshalIndent(ojson.Value(b), "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling outputs")
+	}
+	if string(aj) == string(bj) {
+		return "", nil
+	}
+
+	return utils.Diff(string(aj), string(bj), "previous", "current")
+}
+
+// change describes a change of the files in a root.
+type change struct {
+	// file is one of the changed files, relative to the root.
+	file string
+	// snap is the snapshot of the root after the change.
+	snap map[string]fileState
+}
+
+// waitForChange polls root until a file differs from snap, and returns the change.
+//
+// It returns a nil change when ctx is canceled or done is closed first,
+// done can be nil to wait for a change indefinitely.
+func waitForChange(ctx context.Context, root string, include includeFunc, snap map[string]fileState, done <-chan struct{}) (*change, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-done:
+			return nil, nil
+		case <-ticker.C:
+			next, err := snapshot(root, include)
+			if err != nil {
+				return nil, err
+			}
+			if file, ok := diffSnapshots(snap, next); ok {
+				rel, err := filepath.Rel(root, file)
+				if err != nil {
+					rel = file
+				}
+				return &change{file: rel, snap: next}, nil
+			}
+		}
+	}
+}
+
+type includeFunc func(string, os.FileInfo) (bool, error)
+
+// fileState is the state of a file used to detect changes.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshot returns the state of all files in root that
+// are not excluded by the build ignore rules.
+func snapshot(root string, include includeFunc) (map[string]fileState, error) {
+	snap := map[string]fileState{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				// The file was removed while walking.
+				return nil
+			}
+			return err
+		}
+
+		if ok, err := include(path, info); err != nil {
+			return err
+		} else if !ok {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !info.IsDir() {
+			snap[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+		return nil
+	})
+	return snap, errors.Wrapf(err, "walking %s", root)
+}
+
+// diffSnapshots returns a file that was added, removed or changed between a and b.
+func diffSnapshots(a, b map[string]fileState) (string, bool) {
+	for path, sa := range a {
+		if sb, ok := b[path]; !ok || !sa.modTime.Equal(sb.modTime) || sa.size != sb.size {
+			return path, true
+		}
+	}
+	for path := range b {
+		if _, ok := a[path]; !ok {
+			return path, true
+		}
+	}
+	return "", false
+}
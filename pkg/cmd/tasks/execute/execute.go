@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/pkg/analytics"
@@ -27,8 +26,12 @@ import (
 type config struct {
 	root *cli.Config
 	// task reference could be a script file, yaml definition or a slug.
-	task string
-	args []string
+	task   string
+	args   []string
+	detach bool
+
+	paramsFile string
+	paramsJSON string
 }
 
 // New returns a new execute cobra command.
@@ -39,11 +42,26 @@ func New(c *cli.Config) *cobra.Command {
 		Use:     "execute <slug>",
 		Short:   "Execute a task",
 		Aliases: []string{"exec"},
-		Long:    "Execute a task from the CLI, optionally with specific parameters.",
+		Long: heredoc.Doc(`
+			Execute a task from the CLI, optionally with specific parameters.
+
+			With --output json, log lines, status changes and outputs are printed to stdout
+			as newline-delimited JSON events, such as {"type":"status","ts":...,"status":"Active"}.
+
+			With --detach, the run is started in the background and only its ID is printed,
+			so that it can be waited for later with airplane runs wait.
+
+			Parameter values can also be read from a JSON or YAML file with --params-file,
+			or as JSON with --params-json, which reads stdin if it is "-". Parameters passed
+			as flags after -- override them.
+		`),
 		Example: heredoc.Doc(`
 			airplane execute ./task.js [-- <parameters...>]
 			airplane execute hello_world [-- <parameters...>]
 			airplane execute ./airplane.yml [-- <parameters...>]
+			airplane execute hello_world --detach -- --name Alice
+			airplane execute hello_world --params-file params.yaml
+			echo '{"name": "Alice"}' | airplane execute hello_world --params-json -
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
@@ -66,6 +84,9 @@ func New(c *cli.Config) *cobra.Command {
 
 	cmd.Flags().StringVarP(&cfg.task, "file", "f", "", "File to deploy (.yaml, .yml, .js, .ts)")
 	cli.Must(cmd.Flags().MarkHidden("file")) // --file is deprecated
+	cmd.Flags().BoolVar(&cfg.detach, "detach", false, "Start the run without waiting for it, and print its ID")
+	cmd.Flags().StringVar(&cfg.paramsFile, "params-file", "", "JSON or YAML file of parameter values, keyed by slug")
+	cmd.Flags().StringVar(&cfg.paramsJSON, "params-json", "", `JSON object of parameter values, keyed by slug, or "-" to read it from stdin`)
 
 	return cmd
 }
@@ -104,13 +125,45 @@ func run(ctx context.Context, cfg config) error {
 
 	logger.Log("Executing %s task: %s", logger.Bold(task.Name), logger.Gray(client.TaskURL(task.Slug)))
 
-	req.ParamValues, err = params.CLI(cfg.args, client, task)
+	values, err := params.ReadValues(cfg.paramsFile, cfg.paramsJSON, task)
+	if err != nil {
+		return err
+	}
+	req.ParamValues, err = params.CLI(cfg.args, task, values, params.APIUploader(ctx, client))
 	if errors.Is(err, flag.ErrHelp) {
 		return nil
 	} else if err != nil {
 		return err
 	}
 
+	if cfg.detach {
+		return detach(ctx, cfg.root, req)
+	}
+	return Watch(ctx, cfg.root, task, req)
+}
+
+// detach starts a run and prints its ID, without waiting for it.
+func detach(ctx context.Context, c *cli.Config, req api.RunTaskRequest) error {
+	resp, err := c.Client.RunTask(ctx, req)
+	if err != nil {
+		return errors.Wrap(err, "run task")
+	}
+
+	logger.Log(logger.Gray("Queued run: %s", c.Client.RunURL(resp.RunID)))
+	print.Print(resp, func() {
+		fmt.Fprintln(os.Stdout, resp.RunID)
+	})
+	return nil
+}
+
+// Watch runs a task and streams its logs, status and outputs until the run stops.
+//
+// It returns an error if the run did not succeed.
+func Watch(ctx context.Context, c *cli.Config, task api.Task, req api.RunTaskRequest) error {
+	client := c.Client
+
+	// The context is canceled by a trapped signal (e.g. Ctrl-C), in
+	// which case the watcher cancels the run and waits for it to stop.
 	w, err := client.Watcher(ctx, req)
 	if err != nil {
 		return err
@@ -119,23 +172,28 @@ func run(ctx context.Context, cfg config) error {
 	logger.Log(logger.Gray("Queued run: %s", client.RunURL(w.RunID())))
 
 	var state api.RunState
-	agentPrefix := "[agent]"
+	var status api.RunStatus
 
 	for {
 		if state = w.Next(); state.Err() != nil {
 			break
 		}
 
-		for _, l := range state.Logs {
-			var loggedText string
-			if strings.HasPrefix(l.Text, agentPrefix) {
-				// De-emphasize agent logs and remove prefix
-				loggedText = logger.Gray(strings.TrimLeft(strings.TrimPrefix(l.Text, agentPrefix), " "))
-			} else {
-				// Try to leave user logs alone, so they can apply their own colors
-				loggedText = fmt.Sprintf("[%s] %s", logger.Gray("log"), l.Text)
+		if print.Events() {
+			// Logs are printed first, since they were written before the run got to its status.
+			print.LogEvents(w.RunID(), state.Logs)
+			if state.Status != status {
+				status = state.Status
+				print.PrintEvent(print.Event{Type: print.EventStatus, RunID: w.RunID(), Status: status})
+			}
+			if state.Stopped() {
+				break
 			}
-			logger.Log(loggedText)
+			continue
+		}
+
+		for _, l := range state.Logs {
+			logger.Log(print.LogText(l))
 		}
 
 		if state.Stopped() {
@@ -147,9 +205,13 @@ func run(ctx context.Context, cfg config) error {
 		return err
 	}
 
-	print.Outputs(state.Outputs)
+	if print.Events() {
+		print.OutputsEvent(w.RunID(), state.Outputs)
+	} else {
+		print.Outputs(state.Outputs)
+	}
 
-	analytics.Track(cfg.root, "Run Executed", map[string]interface{}{
+	analytics.Track(c, "Run Executed", map[string]interface{}{
 		"task_id":   task.ID,
 		"task_name": task.Name,
 		"status":    state.Status,
@@ -158,6 +220,8 @@ func run(ctx context.Context, cfg config) error {
 	switch state.Status {
 	case api.RunFailed:
 		return errors.New("Run has failed")
+	case api.RunCancelled:
+		return errors.New("Run has been cancelled")
 	}
 	return nil
 }
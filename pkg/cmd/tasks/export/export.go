@@ -0,0 +1,146 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/taskdir/definitions"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/lib/pkg/build"
+	"github.com/airplanedev/lib/pkg/utils/fsx"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root   *cli.Config
+	slug   string
+	format string
+	file   string
+	force  bool
+}
+
+// New returns a new export command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{root: c}
+
+	cmd := &cobra.Command{
+		Use:   "export <slug>",
+		Short: "Export a task as a task definition",
+		Long: heredoc.Doc(`
+			Exports a task as a task definition, so that a task created in the UI
+			can be checked into a repository and deployed with "airplane deploy".
+
+			The definition is printed to stdout, unless a file is given with --file.
+			The query of a SQL task is written to <slug>.sql next to the file.
+			Existing files are only overwritten after confirming, or with --force.
+		`),
+		Example: heredoc.Doc(`
+			airplane tasks export my_task
+			airplane tasks export my_task --format json
+			airplane tasks export my_task --file my_task.task.yaml
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.slug = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.format, "format", "", `One of "json" or "yaml". Defaults to the format of --file, or "yaml".`)
+	// -o is taken by the global --output flag.
+	cmd.Flags().StringVarP(&cfg.file, "file", "f", "", "File to write the task definition to, instead of stdout.")
+
+	cmd.Flags().BoolVar(&cfg.force, "force", false, "Overwrite existing files without asking.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	client := cfg.root.Client
+
+	format := definitions.TaskDefFormat(cfg.format)
+	if format == "" {
+		format = definitions.TaskDefFormatYAML
+		if f := definitions.GetTaskDefFormat(cfg.file); f != definitions.TaskDefFormatUnknown {
+			format = f
+		}
+	}
+	if format != definitions.TaskDefFormatYAML && format != definitions.TaskDefFormatJSON {
+		return errors.Errorf("Invalid \"format\" specified: %s", cfg.format)
+	}
+
+	task, err := client.GetTask(ctx, cfg.slug)
+	if err != nil {
+		return errors.Wrap(err, "getting task")
+	}
+
+	def, err := definitions.NewDefinitionFromTask_0_3(ctx, client, task)
+	if err != nil {
+		return errors.Wrap(err, "converting task")
+	}
+
+	buf, err := def.Marshal(format)
+	if err != nil {
+		return err
+	}
+	if format == definitions.TaskDefFormatYAML {
+		buf = append([]byte(definitions.SchemaHeader_0_3), buf...)
+	}
+
+	if cfg.file == "" {
+		fmt.Fprint(os.Stdout, string(buf))
+		if task.Kind == build.TaskKindSQL {
+			logger.Warning("The query of the task is not included, export it with --file to write it to %s.", def.SQL.Entrypoint)
+		}
+		return nil
+	}
+
+	files := []string{cfg.file}
+	var entrypoint string
+	if task.Kind == build.TaskKindSQL {
+		entrypoint = filepath.Join(filepath.Dir(cfg.file), def.SQL.Entrypoint)
+		files = append(files, entrypoint)
+	}
+	for _, file := range files {
+		if ok, err := confirmOverwrite(cfg, file); err != nil {
+			return err
+		} else if !ok {
+			// User answered "no", so bail here.
+			return nil
+		}
+	}
+
+	if entrypoint != "" {
+		query, _ := task.KindOptions["query"].(string)
+		if err := ioutil.WriteFile(entrypoint, []byte(query), 0644); err != nil {
+			return errors.Wrapf(err, "writing %s", entrypoint)
+		}
+		logger.Step("Created %s", entrypoint)
+	}
+
+	if err := ioutil.WriteFile(cfg.file, buf, 0644); err != nil {
+		return errors.Wrapf(err, "writing %s", cfg.file)
+	}
+	logger.Step("Created %s", cfg.file)
+
+	return nil
+}
+
+// confirmOverwrite returns whether the file can be written, asking the
+// user first if it already exists.
+func confirmOverwrite(cfg config, file string) (bool, error) {
+	if cfg.force || !fsx.Exists(file) {
+		return true, nil
+	}
+	if !utils.CanPrompt() {
+		return false, errors.Errorf("%s already exists, use --force to overwrite it", file)
+	}
+	return utils.Confirm(fmt.Sprintf("Would you like to overwrite %s?", file))
+}
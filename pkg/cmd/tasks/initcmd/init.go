@@ -214,6 +214,9 @@ func initWithTaskDef(ctx context.Context, cfg config) error {
 	if err != nil {
 		return err
 	}
+	if cfg.defFormat == string(definitions.TaskDefFormatYAML) {
+		buf = append([]byte(definitions.SchemaHeader_0_3), buf...)
+	}
 
 	if err := ioutil.WriteFile(defFn, buf, 0644); err != nil {
 		return err
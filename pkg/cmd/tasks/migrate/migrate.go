@@ -0,0 +1,133 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/taskdir/definitions"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/lib/pkg/utils/fsx"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root  *cli.Config
+	file  string
+	write bool
+}
+
+// New returns a new migrate command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{root: c}
+
+	cmd := &cobra.Command{
+		Use:   "migrate <file>",
+		Short: "Migrates an airplane.yml file to a task definition",
+		Long: heredoc.Doc(`
+			Migrates a task definition in an older format, such as an airplane.yml file,
+			to a <slug>.task.yaml file next to it.
+
+			Parameters, arguments, env vars and resources are converted to the new format,
+			and comments are kept where the fields they were attached to still exist.
+
+			The changes are printed as a diff. Use --write to create the new file, and the
+			query file of SQL tasks. The old file is left as is.
+		`),
+		Example: heredoc.Doc(`
+			$ airplane tasks migrate airplane.yml
+			$ airplane tasks migrate ./my_task/airplane.yml --write
+		`),
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: utils.RootPersistentPreRunE,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.file = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().BoolVar(&cfg.write, "write", false, "Write the migrated task definition instead of only printing a diff.")
+
+	return cmd
+}
+
+// Run runs the migrate command.
+func run(ctx context.Context, cfg config) error {
+	if definitions.IsTaskDef(cfg.file) {
+		return errors.Errorf("%s is already a task definition in the latest format", cfg.file)
+	}
+
+	buf, err := ioutil.ReadFile(cfg.file)
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", cfg.file)
+	}
+	def, err := definitions.UnmarshalDefinition(buf, cfg.file)
+	if err != nil {
+		return err
+	}
+
+	newDef, err := def.Migrate_0_3()
+	if err != nil {
+		return errors.Wrap(err, "migrating task definition")
+	}
+	out, err := definitions.MarshalMigrated_0_3(buf, newDef)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(cfg.file)
+	newFile := filepath.Join(dir, def.Slug+".task.yaml")
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(buf)),
+		B:        difflib.SplitLines(string(out)),
+		FromFile: cfg.file,
+		ToFile:   newFile,
+		Context:  3,
+	})
+	if err != nil {
+		return errors.Wrap(err, "computing diff")
+	}
+	fmt.Fprint(os.Stdout, diff)
+
+	if !cfg.write {
+		logger.Suggest(
+			"⚡ To write the migrated task definition:",
+			"airplane tasks migrate %s --write",
+			cfg.file,
+		)
+		return nil
+	}
+
+	if fsx.Exists(newFile) {
+		return errors.Errorf("%s already exists", newFile)
+	}
+	if newDef.SQL != nil {
+		query := filepath.Join(dir, newDef.SQL.Entrypoint)
+		if fsx.Exists(query) {
+			return errors.Errorf("%s already exists", query)
+		}
+		if err := ioutil.WriteFile(query, []byte(def.SQL.Query), 0644); err != nil {
+			return errors.Wrapf(err, "writing %s", query)
+		}
+		logger.Step("Created %s", query)
+	}
+	if err := ioutil.WriteFile(newFile, out, 0644); err != nil {
+		return errors.Wrapf(err, "writing %s", newFile)
+	}
+	logger.Step("Created %s", newFile)
+
+	logger.Log("Once you have checked the new task definition, you can delete %s.", cfg.file)
+	logger.Suggest(
+		"🛫 To deploy your task to Airplane:",
+		"airplane deploy %s",
+		newFile,
+	)
+	return nil
+}
@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"context"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/taskdir/definitions"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new schema command.
+func New(c *cli.Config) *cobra.Command {
+	var version string
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Prints the JSON schema of task definitions",
+		Long: heredoc.Docf(`
+			Prints the JSON schema of task definitions, for editors to validate and
+			complete them. Use --version 0.3 (the default) for *.task.yaml files, or
+			--version 0.2 and 0.1 for airplane.yml files.
+
+			YAML task definitions created by airplane tasks init start with a comment
+			that points editors using the YAML language server to the schema:
+
+			    %s`, definitions.SchemaHeader_0_3),
+		Example: heredoc.Doc(`
+			$ airplane tasks schema > task.schema.json
+			$ airplane tasks schema --version 0.2
+		`),
+		Args:              cobra.NoArgs,
+		PersistentPreRunE: utils.RootPersistentPreRunE,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), c, version)
+		},
+	}
+	cmd.Flags().StringVar(&version, "version", "0.3", "Version of the task definitions, 0.3 for *.task.yaml files or 0.2 and 0.1 for airplane.yml files.")
+	return cmd
+}
+
+// Run runs the schema command.
+func run(ctx context.Context, c *cli.Config, version string) error {
+	buf, err := definitions.Schema(version)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stdout.Write(buf); err != nil {
+		return errors.Wrap(err, "writing schema")
+	}
+	return nil
+}
@@ -7,10 +7,14 @@ import (
 	"github.com/airplanedev/cli/pkg/cmd/tasks/deploy"
 	"github.com/airplanedev/cli/pkg/cmd/tasks/dev"
 	"github.com/airplanedev/cli/pkg/cmd/tasks/execute"
+	"github.com/airplanedev/cli/pkg/cmd/tasks/export"
 	"github.com/airplanedev/cli/pkg/cmd/tasks/get"
 	"github.com/airplanedev/cli/pkg/cmd/tasks/initcmd"
 	"github.com/airplanedev/cli/pkg/cmd/tasks/list"
+	"github.com/airplanedev/cli/pkg/cmd/tasks/migrate"
 	"github.com/airplanedev/cli/pkg/cmd/tasks/open"
+	"github.com/airplanedev/cli/pkg/cmd/tasks/schema"
+	"github.com/airplanedev/cli/pkg/cmd/tasks/validate"
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -27,6 +31,9 @@ func New(c *cli.Config) *cobra.Command {
 			airplane tasks deploy -f mytask.yml
 			airplane tasks get my_task
 			airplane tasks execute my_task
+			airplane tasks export my_task -f my_task.task.yaml
+			airplane tasks validate ./tasks
+			airplane tasks migrate airplane.yml
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
@@ -37,9 +44,13 @@ func New(c *cli.Config) *cobra.Command {
 	cmd.AddCommand(list.New(c))
 	cmd.AddCommand(dev.New(c))
 	cmd.AddCommand(execute.New(c))
+	cmd.AddCommand(export.New(c))
 	cmd.AddCommand(get.New(c))
 	cmd.AddCommand(initcmd.New(c))
+	cmd.AddCommand(migrate.New(c))
 	cmd.AddCommand(open.New(c))
+	cmd.AddCommand(schema.New(c))
+	cmd.AddCommand(validate.New(c))
 
 	return cmd
 }
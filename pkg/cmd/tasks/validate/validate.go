@@ -0,0 +1,107 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/taskdir/definitions"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new validate command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [paths...]",
+		Short: "Validates task definitions",
+		Long: heredoc.Doc(`
+			Validates the task definitions (*.task.yaml, *.task.yml and *.task.json files)
+			found in the given paths, which default to the current directory.
+
+			All problems are reported with their position in the file, and the command
+			exits with a non-zero status if any are found. It does not require logging in.
+		`),
+		Example: heredoc.Doc(`
+			$ airplane tasks validate
+			$ airplane tasks validate ./tasks my_task.task.yaml
+		`),
+		PersistentPreRunE: utils.RootPersistentPreRunE,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				args = []string{"."}
+			}
+			return run(cmd.Root().Context(), c, args)
+		},
+	}
+	return cmd
+}
+
+// problem is a validation error of a task definition, as printed.
+type problem struct {
+	File    string `json:"file" yaml:"file"`
+	Line    int    `json:"line,omitempty" yaml:"line,omitempty"`
+	Column  int    `json:"column,omitempty" yaml:"column,omitempty"`
+	Path    string `json:"path" yaml:"path"`
+	Message string `json:"message" yaml:"message"`
+}
+
+func (p problem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s", p.File, p.Path, p.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", p.File, p.Line, p.Column, p.Path, p.Message)
+}
+
+// Run runs the validate command.
+func run(ctx context.Context, c *cli.Config, paths []string) error {
+	defPaths, err := definitions.DiscoverTaskDefs(paths...)
+	if err != nil {
+		return err
+	}
+	if len(defPaths) == 0 {
+		return errors.Errorf("no task definitions found in %v", paths)
+	}
+
+	problems := []problem{}
+	var invalid int
+	for _, defPath := range defPaths {
+		buf, err := ioutil.ReadFile(defPath)
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", defPath)
+		}
+		verrs, err := definitions.Validate_0_3(buf, defPath)
+		if err != nil {
+			return errors.Wrapf(err, "validating %s", defPath)
+		}
+		if len(verrs) > 0 {
+			invalid++
+		}
+		for _, verr := range verrs {
+			problems = append(problems, problem{
+				File:    defPath,
+				Line:    verr.Line,
+				Column:  verr.Column,
+				Path:    verr.Path,
+				Message: verr.Message,
+			})
+		}
+	}
+
+	print.Print(problems, func() {
+		for _, p := range problems {
+			logger.Log("%s", p)
+		}
+	})
+
+	if len(problems) > 0 {
+		return errors.Errorf("found %d problem(s) in %d of %d task definition(s)", len(problems), invalid, len(defPaths))
+	}
+	logger.Log("All %d task definition(s) are valid.", len(defPaths))
+	return nil
+}
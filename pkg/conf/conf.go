@@ -18,6 +18,34 @@ var (
 type Config struct {
 	Tokens          map[string]string `json:"tokens,omitempty"`
 	EnableTelemetry *bool             `json:"enableTelemetry,omitempty"`
+
+	// Profiles are named sets of settings, selected with
+	// --profile or AIRPLANE_PROFILE.
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+	// DefaultProfile is the profile used when none is selected.
+	DefaultProfile string `json:"defaultProfile,omitempty"`
+
+	// CredentialStore is the backend that tokens are stored in,
+	// Tokens are stored in this config when it is empty.
+	CredentialStore string `json:"credentialStore,omitempty"`
+}
+
+// Profile represents a named set of settings.
+//
+// Flags and env vars take precedence over the settings of a profile.
+type Profile struct {
+	// Host is the API host, defaults to the --host flag.
+	Host string `json:"host,omitempty"`
+	// Token is set when logging in with the profile, and APIKey
+	// when adding it with an API key, with the plaintext credential store.
+	Token  string `json:"token,omitempty"`
+	APIKey string `json:"apiKey,omitempty"`
+	TeamID string `json:"teamID,omitempty"`
+	// Output is the default output format (json|yaml|table).
+	Output string `json:"output,omitempty"`
+	// Env are env vars that apply when the profile is used, unless
+	// they are set in the environment, e.g. AP_GIT_REPO.
+	Env map[string]string `json:"env,omitempty"`
 }
 
 // Path returns the default config path.
@@ -82,22 +110,7 @@ func WriteDefault(cfg Config) error {
 	return Write(path(), cfg)
 }
 
-// GetAPIKey gets an Airplane API key from an env var, if one exists.
-func GetAPIKey() string {
-	return os.Getenv("AP_API_KEY")
-}
-
-// GetTeamID gets an Airplane team ID from an env var, if one exists.
-func GetTeamID() string {
-	return os.Getenv("AP_TEAM_ID")
-}
-
-// GetGitRepo gets a git repo from an env var, if one exists.
-func GetGitRepo() string {
-	return os.Getenv("AP_GIT_REPO")
-}
-
-// GetGitUser gets a git user from an env var, if one exists.
-func GetGitUser() string {
-	return os.Getenv("AP_GIT_USER")
+// GetProfile gets the name of the selected profile from an env var, if one exists.
+func GetProfile() string {
+	return os.Getenv("AIRPLANE_PROFILE")
 }
@@ -0,0 +1,443 @@
+package conf
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Credential store backends.
+const (
+	// CredentialStorePlaintext stores tokens in the config file.
+	CredentialStorePlaintext = "plaintext"
+	// CredentialStoreFile stores tokens in a file encrypted with a passphrase.
+	CredentialStoreFile = "file"
+	// CredentialStoreKeyring stores tokens in the system keyring.
+	CredentialStoreKeyring = "keyring"
+)
+
+// ErrPassphraseRequired is returned by the encrypted file store when
+// there is no passphrase to decrypt it with.
+var ErrPassphraseRequired = errors.New("set AIRPLANE_CREDENTIALS_PASSPHRASE to use the encrypted credential store")
+
+// CredentialKey identifies a token in a credential store.
+//
+// Tokens are stored per profile, or per host when logging in without a profile.
+// The API key of a profile is stored like its token.
+type CredentialKey struct {
+	Profile string
+	Host    string
+	// APIKey identifies the API key of the profile instead of its token.
+	APIKey bool
+}
+
+func (k CredentialKey) String() string {
+	if k.Profile != "" && k.APIKey {
+		return "profile-api-key:" + k.Profile
+	}
+	if k.Profile != "" {
+		return "profile:" + k.Profile
+	}
+	return "host:" + k.Host
+}
+
+// profileField returns the field of p that the plaintext store keeps the
+// token or API key of k in.
+func (k CredentialKey) profileField(p *Profile) *string {
+	if k.APIKey {
+		return &p.APIKey
+	}
+	return &p.Token
+}
+
+// CredentialStore stores the tokens the CLI logs in with.
+type CredentialStore interface {
+	// Token returns the token with the given key, or an empty string if there is none.
+	Token(key CredentialKey) (string, error)
+	// SetToken stores a token.
+	SetToken(key CredentialKey, token string) error
+	// DeleteToken removes a token, it does nothing if there is none.
+	DeleteToken(key CredentialKey) error
+}
+
+// TokenKey returns the key that a token of profile on host is stored with.
+//
+// Profiles that do not exist are ignored.
+func TokenKey(cfg Config, profile, host string) CredentialKey {
+	if _, ok := cfg.Profiles[profile]; ok {
+		return CredentialKey{Profile: profile}
+	}
+	return CredentialKey{Host: host}
+}
+
+// GetToken returns the token of the given profile, or the token of host
+// if profile is empty or was not logged in to.
+func GetToken(store CredentialStore, profile, host string) (string, error) {
+	if profile != "" {
+		token, err := store.Token(CredentialKey{Profile: profile})
+		if err != nil || token != "" {
+			return token, err
+		}
+	}
+	return store.Token(CredentialKey{Host: host})
+}
+
+// CredentialStoreOptions configures OpenCredentialStore.
+type CredentialStoreOptions struct {
+	// ConfigPath is the path of the config, defaults to ~/.airplane/config.
+	ConfigPath string
+	// Passphrase returns the passphrase of the encrypted file store,
+	// if it is not set with AIRPLANE_CREDENTIALS_PASSPHRASE. See NewFileStore.
+	Passphrase func(confirm bool) (string, error)
+	// Keyring is the keyring of the keyring store, defaults to the system keyring.
+	Keyring Keyring
+}
+
+// OpenCredentialStore opens the credential store selected with
+// AIRPLANE_CREDENTIAL_STORE, or configured in cfg.
+//
+// The first time another store than the plaintext one is used, tokens stored
+// in plaintext in the config are moved to it, and the store is saved to the config.
+func OpenCredentialStore(cfg Config, opts CredentialStoreOptions) (CredentialStore, error) {
+	if opts.ConfigPath == "" {
+		opts.ConfigPath = path()
+	}
+
+	name := os.Getenv("AIRPLANE_CREDENTIAL_STORE")
+	if name == "" {
+		name = cfg.CredentialStore
+	}
+
+	var store CredentialStore
+	switch name {
+	case "", CredentialStorePlaintext:
+		return plaintextStore{path: opts.ConfigPath}, nil
+	case CredentialStoreFile:
+		store = NewFileStore(filepath.Join(filepath.Dir(opts.ConfigPath), "credentials"), opts.Passphrase)
+	case CredentialStoreKeyring:
+		k := opts.Keyring
+		if k == nil {
+			k = SystemKeyring()
+		}
+		store = NewKeyringStore(k)
+	default:
+		return nil, errors.Errorf("unknown credential store %q, expected one of: %s, %s, %s",
+			name, CredentialStorePlaintext, CredentialStoreFile, CredentialStoreKeyring)
+	}
+
+	migrated, err := migrateCredentials(&cfg, store)
+	if err != nil {
+		return nil, errors.Wrapf(err, "moving tokens to the %s credential store", name)
+	}
+	if migrated || cfg.CredentialStore != name {
+		cfg.CredentialStore = name
+		if err := Write(opts.ConfigPath, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	return store, nil
+}
+
+// migrateCredentials moves the tokens and API keys stored in plaintext in cfg
+// to store, and returns true if there were any.
+func migrateCredentials(cfg *Config, store CredentialStore) (bool, error) {
+	var migrated bool
+	for host, token := range cfg.Tokens {
+		if err := store.SetToken(CredentialKey{Host: host}, token); err != nil {
+			return false, err
+		}
+		delete(cfg.Tokens, host)
+		migrated = true
+	}
+	for profile, p := range cfg.Profiles {
+		for _, key := range []CredentialKey{{Profile: profile}, {Profile: profile, APIKey: true}} {
+			field := key.profileField(&p)
+			if *field == "" {
+				continue
+			}
+			if err := store.SetToken(key, *field); err != nil {
+				return false, err
+			}
+			*field = ""
+			migrated = true
+		}
+		cfg.Profiles[profile] = p
+	}
+	return migrated, nil
+}
+
+// plaintextStore stores tokens in the config file.
+type plaintextStore struct {
+	path string
+}
+
+func (s plaintextStore) Token(key CredentialKey) (string, error) {
+	cfg, err := Read(s.path)
+	if errors.Is(err, ErrMissing) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if key.Profile != "" {
+		p := cfg.Profiles[key.Profile]
+		return *key.profileField(&p), nil
+	}
+	return cfg.Tokens[key.Host], nil
+}
+
+func (s plaintextStore) SetToken(key CredentialKey, token string) error {
+	cfg, err := Read(s.path)
+	if err != nil && !errors.Is(err, ErrMissing) {
+		return err
+	}
+
+	if key.Profile != "" {
+		p, ok := cfg.Profiles[key.Profile]
+		if !ok {
+			return errors.Errorf("profile %q does not exist", key.Profile)
+		}
+		*key.profileField(&p) = token
+		cfg.Profiles[key.Profile] = p
+	} else if token == "" {
+		delete(cfg.Tokens, key.Host)
+	} else {
+		if cfg.Tokens == nil {
+			cfg.Tokens = map[string]string{}
+		}
+		cfg.Tokens[key.Host] = token
+	}
+
+	return Write(s.path, cfg)
+}
+
+func (s plaintextStore) DeleteToken(key CredentialKey) error {
+	if key.Profile != "" {
+		if token, err := s.Token(key); err != nil || token == "" {
+			// There is nothing to delete.
+			return nil
+		}
+	}
+	return s.SetToken(key, "")
+}
+
+// fileStore stores tokens in a file encrypted with AES-GCM, using
+// a key derived from a passphrase with scrypt.
+type fileStore struct {
+	path       string
+	passphrase func(confirm bool) (string, error)
+
+	mu sync.Mutex
+	// key is the encryption key, derived from the passphrase on first use.
+	key  []byte
+	salt []byte
+}
+
+// encryptedFile is the format of the file of a file store.
+type encryptedFile struct {
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// NewFileStore returns a credential store that stores tokens in the file at
+// path, encrypted with the passphrase set with AIRPLANE_CREDENTIALS_PASSPHRASE
+// or otherwise returned by passphrase.
+//
+// When the file is created, passphrase is called a second time with confirm
+// set, and the store fails if it returns another passphrase, so that a typo
+// does not lock the user out of their tokens.
+func NewFileStore(path string, passphrase func(confirm bool) (string, error)) CredentialStore {
+	return &fileStore{path: path, passphrase: passphrase}
+}
+
+func (s *fileStore) Token(key CredentialKey) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	return tokens[key.String()], nil
+}
+
+func (s *fileStore) SetToken(key CredentialKey, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	tokens[key.String()] = token
+	return s.write(tokens)
+}
+
+func (s *fileStore) DeleteToken(key CredentialKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[key.String()]; !ok {
+		return nil
+	}
+	delete(tokens, key.String())
+	return s.write(tokens)
+}
+
+func (s *fileStore) read() (map[string]string, error) {
+	tokens := map[string]string{}
+	buf, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "read credentials")
+	}
+
+	var f encryptedFile
+	if err := json.Unmarshal(buf, &f); err != nil {
+		return nil, errors.Wrap(err, "unmarshal credentials")
+	}
+	gcm, err := s.cipher(f.Salt, false)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, f.Nonce, f.Ciphertext, nil)
+	if err != nil {
+		// Forget the key, it was derived from the wrong passphrase.
+		s.key = nil
+		return nil, errors.New("decrypting credentials: wrong passphrase?")
+	}
+	if err := json.Unmarshal(plaintext, &tokens); err != nil {
+		return nil, errors.Wrap(err, "unmarshal credentials")
+	}
+	return tokens, nil
+}
+
+func (s *fileStore) write(tokens map[string]string) error {
+	plaintext, err := json.Marshal(tokens)
+	if err != nil {
+		return errors.Wrap(err, "marshal credentials")
+	}
+
+	salt, create := s.salt, s.salt == nil
+	if create {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return errors.Wrap(err, "generating salt")
+		}
+	}
+	gcm, err := s.cipher(salt, create)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return errors.Wrap(err, "generating nonce")
+	}
+
+	buf, err := json.MarshalIndent(encryptedFile{
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "	")
+	if err != nil {
+		return errors.Wrap(err, "marshal credentials")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0777); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	if err := ioutil.WriteFile(s.path, buf, 0600); err != nil {
+		return errors.Wrap(err, "write credentials")
+	}
+	return nil
+}
+
+// cipher returns the cipher for the given salt, deriving
+// the key from the passphrase if needed.
+//
+// If create is set, the file is being created and the
+// passphrase is confirmed.
+func (s *fileStore) cipher(salt []byte, create bool) (cipher.AEAD, error) {
+	if s.key == nil || string(s.salt) != string(salt) {
+		passphrase := os.Getenv("AIRPLANE_CREDENTIALS_PASSPHRASE")
+		if passphrase == "" {
+			if s.passphrase == nil {
+				return nil, ErrPassphraseRequired
+			}
+			var err error
+			if passphrase, err = s.passphrase(false); err != nil {
+				return nil, err
+			}
+			if create {
+				confirmed, err := s.passphrase(true)
+				if err != nil {
+					return nil, err
+				}
+				if confirmed != passphrase {
+					return nil, errors.New("the passphrases do not match")
+				}
+			}
+		}
+
+		key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+		if err != nil {
+			return nil, errors.Wrap(err, "deriving key")
+		}
+		s.key, s.salt = key, salt
+	}
+
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	return gcm, errors.Wrap(err, "creating cipher")
+}
+
+// keyringService is the service that tokens are stored under in keyrings.
+const keyringService = "airplane-cli"
+
+// keyringStore stores tokens in a keyring.
+type keyringStore struct {
+	keyring Keyring
+}
+
+// NewKeyringStore returns a credential store that stores tokens in k.
+func NewKeyringStore(k Keyring) CredentialStore {
+	return keyringStore{keyring: k}
+}
+
+func (s keyringStore) Token(key CredentialKey) (string, error) {
+	token, err := s.keyring.Get(keyringService, key.String())
+	if errors.Is(err, ErrKeyringMissing) {
+		return "", nil
+	}
+	return token, err
+}
+
+func (s keyringStore) SetToken(key CredentialKey, token string) error {
+	return s.keyring.Set(keyringService, key.String(), token)
+}
+
+func (s keyringStore) DeleteToken(key CredentialKey) error {
+	err := s.keyring.Delete(keyringService, key.String())
+	if errors.Is(err, ErrKeyringMissing) {
+		return nil
+	}
+	return err
+}
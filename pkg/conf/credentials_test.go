@@ -0,0 +1,167 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKeyring map[string]string
+
+func (k fakeKeyring) Get(service, user string) (string, error) {
+	secret, ok := k[service+"/"+user]
+	if !ok {
+		return "", ErrKeyringMissing
+	}
+	return secret, nil
+}
+
+func (k fakeKeyring) Set(service, user, secret string) error {
+	k[service+"/"+user] = secret
+	return nil
+}
+
+func (k fakeKeyring) Delete(service, user string) error {
+	if _, ok := k[service+"/"+user]; !ok {
+		return ErrKeyringMissing
+	}
+	delete(k, service+"/"+user)
+	return nil
+}
+
+func TestCredentialStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) CredentialStore{
+		"plaintext": func(t *testing.T) CredentialStore {
+			path := filepath.Join(tempdir(t), "config")
+			require.NoError(t, Write(path, Config{
+				Profiles: map[string]Profile{"staging": {}},
+			}))
+			return plaintextStore{path: path}
+		},
+		"file": func(t *testing.T) CredentialStore {
+			path := filepath.Join(tempdir(t), "credentials")
+			return NewFileStore(path, func(bool) (string, error) { return "hunter2", nil })
+		},
+		"keyring": func(t *testing.T) CredentialStore {
+			return NewKeyringStore(fakeKeyring{})
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			var assert = require.New(t)
+			store := newStore(t)
+
+			token, err := GetToken(store, "", "airplane.dev")
+			assert.NoError(err)
+			assert.Equal("", token)
+
+			assert.NoError(store.SetToken(CredentialKey{Host: "airplane.dev"}, "foo"))
+			token, err = GetToken(store, "", "airplane.dev")
+			assert.NoError(err)
+			assert.Equal("foo", token)
+			// Profiles that were not logged in to use the token of the host.
+			token, err = GetToken(store, "staging", "airplane.dev")
+			assert.NoError(err)
+			assert.Equal("foo", token)
+
+			assert.NoError(store.SetToken(CredentialKey{Profile: "staging"}, "bar"))
+			token, err = GetToken(store, "staging", "airplane.dev")
+			assert.NoError(err)
+			assert.Equal("bar", token)
+
+			// API keys are stored apart from tokens.
+			apiKey := CredentialKey{Profile: "staging", APIKey: true}
+			assert.NoError(store.SetToken(apiKey, "baz"))
+			token, err = store.Token(apiKey)
+			assert.NoError(err)
+			assert.Equal("baz", token)
+			assert.NoError(store.DeleteToken(apiKey))
+			token, err = store.Token(apiKey)
+			assert.NoError(err)
+			assert.Equal("", token)
+
+			assert.NoError(store.DeleteToken(CredentialKey{Host: "airplane.dev"}))
+			assert.NoError(store.DeleteToken(CredentialKey{Host: "airplane.dev"}))
+			token, err = GetToken(store, "", "airplane.dev")
+			assert.NoError(err)
+			assert.Equal("", token)
+			token, err = GetToken(store, "staging", "airplane.dev")
+			assert.NoError(err)
+			assert.Equal("bar", token)
+		})
+	}
+
+	t.Run("file with wrong passphrase", func(t *testing.T) {
+		var assert = require.New(t)
+		path := filepath.Join(tempdir(t), "credentials")
+
+		store := NewFileStore(path, func(bool) (string, error) { return "hunter2", nil })
+		assert.NoError(store.SetToken(CredentialKey{Host: "airplane.dev"}, "foo"))
+
+		buf, err := os.ReadFile(path)
+		assert.NoError(err)
+		assert.NotContains(string(buf), "foo")
+
+		store = NewFileStore(path, func(bool) (string, error) { return "hunter3", nil })
+		_, err = store.Token(CredentialKey{Host: "airplane.dev"})
+		assert.Error(err)
+	})
+
+	t.Run("file with unconfirmed passphrase", func(t *testing.T) {
+		var assert = require.New(t)
+		path := filepath.Join(tempdir(t), "credentials")
+
+		store := NewFileStore(path, func(confirm bool) (string, error) {
+			if confirm {
+				return "hunter3", nil
+			}
+			return "hunter2", nil
+		})
+		assert.EqualError(store.SetToken(CredentialKey{Host: "airplane.dev"}, "foo"), "the passphrases do not match")
+		_, err := os.Stat(path)
+		assert.True(os.IsNotExist(err))
+	})
+}
+
+func TestOpenCredentialStore(t *testing.T) {
+	var assert = require.New(t)
+	path := filepath.Join(tempdir(t), "config")
+	cfg := Config{
+		Tokens:   map[string]string{"airplane.dev": "foo"},
+		Profiles: map[string]Profile{"staging": {Host: "airstage.app", Token: "bar", APIKey: "baz", TeamID: "tea1"}},
+	}
+	assert.NoError(Write(path, cfg))
+
+	os.Setenv("AIRPLANE_CREDENTIAL_STORE", CredentialStoreKeyring)
+	defer os.Unsetenv("AIRPLANE_CREDENTIAL_STORE")
+
+	keyring := fakeKeyring{}
+	store, err := OpenCredentialStore(cfg, CredentialStoreOptions{
+		ConfigPath: path,
+		Keyring:    keyring,
+	})
+	assert.NoError(err)
+
+	token, err := GetToken(store, "", "airplane.dev")
+	assert.NoError(err)
+	assert.Equal("foo", token)
+	token, err = GetToken(store, "staging", "airplane.dev")
+	assert.NoError(err)
+	assert.Equal("bar", token)
+	token, err = store.Token(CredentialKey{Profile: "staging", APIKey: true})
+	assert.NoError(err)
+	assert.Equal("baz", token)
+
+	// The tokens were removed from the config, which now uses the keyring.
+	cfg, err = Read(path)
+	assert.NoError(err)
+	assert.Empty(cfg.Tokens)
+	assert.Equal("", cfg.Profiles["staging"].Token)
+	assert.Equal("", cfg.Profiles["staging"].APIKey)
+	assert.Equal("tea1", cfg.Profiles["staging"].TeamID)
+	assert.Equal("airstage.app", cfg.Profiles["staging"].Host)
+	assert.Equal(CredentialStoreKeyring, cfg.CredentialStore)
+}
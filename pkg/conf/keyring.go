@@ -0,0 +1,166 @@
+package conf
+
+import (
+	"bytes"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrKeyringMissing is returned by keyrings when a secret does not exist.
+var ErrKeyringMissing = errors.New("conf: secret not found in keyring")
+
+// Keyring stores secrets by service and user, such as the macOS keychain.
+type Keyring interface {
+	// Get returns a secret, or ErrKeyringMissing if it does not exist.
+	Get(service, user string) (string, error)
+	Set(service, user, secret string) error
+	// Delete removes a secret, or returns ErrKeyringMissing if it does not exist.
+	Delete(service, user string) error
+}
+
+// SystemKeyring returns the keyring of the system.
+//
+// It uses the keychain on macOS, and the Secret Service (e.g. GNOME Keyring)
+// through secret-tool on Linux. Other systems are not supported.
+func SystemKeyring() Keyring {
+	switch runtime.GOOS {
+	case "darwin":
+		return macOSKeychain{}
+	case "linux":
+		return secretService{}
+	default:
+		return unsupportedKeyring{}
+	}
+}
+
+// macOSKeychain stores secrets in the login keychain with the security CLI.
+type macOSKeychain struct{}
+
+// errKeychainMissing is the exit code of security when an item does not exist.
+const errKeychainMissing = 44
+
+func (macOSKeychain) Get(service, user string) (string, error) {
+	out, err := runKeyringCmd(nil, "security", "find-generic-password", "-s", service, "-a", user, "-w")
+	if exitCode(err) == errKeychainMissing {
+		return "", ErrKeyringMissing
+	} else if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+func (macOSKeychain) Set(service, user, secret string) error {
+	// The command is read from stdin in interactive mode, so that the secret
+	// does not show up in the process list. -U updates the item if it exists.
+	cmd := keychainCommand("add-generic-password", "-U", "-s", service, "-a", user, "-w", secret)
+	_, stderr, err := runKeyringCmdErr(strings.NewReader(cmd+"\n"), "security", "-i")
+	if err == nil && stderr != "" {
+		// Failed commands don't change the exit code in interactive mode.
+		err = errors.Errorf("security: %s", stderr)
+	}
+	return err
+}
+
+// keychainCommand returns a command of the interactive mode of security,
+// with its arguments quoted.
+func keychainCommand(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		arg = strings.ReplaceAll(arg, `\`, `\\`)
+		quoted[i] = `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+func (macOSKeychain) Delete(service, user string) error {
+	_, err := runKeyringCmd(nil, "security", "delete-generic-password", "-s", service, "-a", user)
+	if exitCode(err) == errKeychainMissing {
+		return ErrKeyringMissing
+	}
+	return err
+}
+
+// secretService stores secrets with the Secret Service API through secret-tool.
+type secretService struct{}
+
+func (secretService) Get(service, user string) (string, error) {
+	out, err := runKeyringCmd(nil, "secret-tool", "lookup", "service", service, "user", user)
+	// secret-tool exits with an error without output when the secret does not exist.
+	if exitCode(err) != -1 && out == "" {
+		return "", ErrKeyringMissing
+	} else if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+func (secretService) Set(service, user, secret string) error {
+	// The secret is read from stdin so that it does not show up in the process list.
+	_, err := runKeyringCmd(strings.NewReader(secret), "secret-tool", "store", "--label", "Airplane CLI ("+user+")", "service", service, "user", user)
+	return err
+}
+
+func (s secretService) Delete(service, user string) error {
+	if _, err := s.Get(service, user); err != nil {
+		return err
+	}
+	_, err := runKeyringCmd(nil, "secret-tool", "clear", "service", service, "user", user)
+	return err
+}
+
+type unsupportedKeyring struct{}
+
+func (unsupportedKeyring) Get(service, user string) (string, error) {
+	return "", errUnsupportedKeyring()
+}
+
+func (unsupportedKeyring) Set(service, user, secret string) error {
+	return errUnsupportedKeyring()
+}
+
+func (unsupportedKeyring) Delete(service, user string) error {
+	return errUnsupportedKeyring()
+}
+
+func errUnsupportedKeyring() error {
+	return errors.Errorf("the keyring credential store is not supported on %s", runtime.GOOS)
+}
+
+func runKeyringCmd(stdin *strings.Reader, name string, args ...string) (string, error) {
+	stdout, _, err := runKeyringCmdErr(stdin, name, args...)
+	return stdout, err
+}
+
+// runKeyringCmdErr is like runKeyringCmd, and also returns the
+// trimmed stderr of the command.
+func runKeyringCmdErr(stdin *strings.Reader, name string, args ...string) (string, string, error) {
+	cmd := exec.Command(name, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	errOut := strings.TrimSpace(stderr.String())
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return stdout.String(), errOut, errors.Wrapf(err, "%s: %s", name, errOut)
+		}
+		return "", errOut, errors.Wrapf(err, "running %s", name)
+	}
+	return stdout.String(), errOut, nil
+}
+
+// exitCode returns the exit code of a failed command, or -1 if
+// err is not an exit error.
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
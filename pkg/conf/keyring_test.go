@@ -0,0 +1,14 @@
+package conf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeychainCommand(t *testing.T) {
+	var assert = require.New(t)
+
+	assert.Equal(`"add-generic-password" "-s" "airplane-cli" "-w" "a \"b\" \\c"`,
+		keychainCommand("add-generic-password", "-s", "airplane-cli", "-w", `a "b" \c`))
+}
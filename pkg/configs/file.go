@@ -0,0 +1,184 @@
+package configs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// File formats that config vars can be imported from and exported to.
+const (
+	FormatEnv  = "env"
+	FormatYAML = "yaml"
+)
+
+// SecretPlaceholder replaces the values of secrets that are exported without
+// their value. Config vars with this value are skipped on import.
+const SecretPlaceholder = "<secret value hidden>"
+
+// envSecretMarker is the comment that marks the next config var of a .env file as a secret.
+const envSecretMarker = "# secret"
+
+// FileConfig is a config var as stored in a file.
+type FileConfig struct {
+	NameTag
+	Value  string
+	Secret bool
+}
+
+// yamlFile is the format of YAML config files.
+type yamlFile struct {
+	Configs []yamlConfig `yaml:"configs"`
+}
+
+type yamlConfig struct {
+	Name   string `yaml:"name"`
+	Tag    string `yaml:"tag,omitempty"`
+	Value  string `yaml:"value"`
+	Secret bool   `yaml:"secret,omitempty"`
+}
+
+// FormatFromPath returns the format of a file based on its extension,
+// defaulting to the .env format.
+func FormatFromPath(path string) string {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatEnv
+	}
+}
+
+// ReadFile reads config vars in the given format.
+//
+// In the .env format, each line is a `name[:tag]=value` pair. Values may be
+// double-quoted with Go escapes, and a `# secret` comment marks the config
+// var on the next line as a secret.
+func ReadFile(r io.Reader, format string) ([]FileConfig, error) {
+	switch format {
+	case FormatEnv:
+		return readEnv(r)
+	case FormatYAML:
+		var f yamlFile
+		if err := yaml.NewDecoder(r).Decode(&f); err != nil && err != io.EOF {
+			return nil, errors.Wrap(err, "decoding yaml")
+		}
+		var configs []FileConfig
+		for _, c := range f.Configs {
+			if c.Name == "" {
+				return nil, errors.New("config vars must have a name")
+			}
+			configs = append(configs, FileConfig{
+				NameTag: NameTag{Name: c.Name, Tag: c.Tag},
+				Value:   c.Value,
+				Secret:  c.Secret,
+			})
+		}
+		return configs, nil
+	default:
+		return nil, errors.Errorf("unknown format %q, expected %s or %s", format, FormatEnv, FormatYAML)
+	}
+}
+
+func readEnv(r io.Reader) ([]FileConfig, error) {
+	var configs []FileConfig
+	var secret bool
+	scanner := bufio.NewScanner(r)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == envSecretMarker {
+			secret = true
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			return nil, errors.Errorf("line %d: expected name=value", lineno)
+		}
+		nt, err := ParseName(strings.TrimSpace(key))
+		if err != nil || nt.Name == "" {
+			return nil, errors.Errorf("line %d: invalid config name %q - expected my_config or my_config:tag", lineno, key)
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(value, `"`):
+			if value, err = strconv.Unquote(value); err != nil {
+				return nil, errors.Errorf("line %d: invalid quoted value", lineno)
+			}
+		case len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'"):
+			value = value[1 : len(value)-1]
+		}
+
+		configs = append(configs, FileConfig{NameTag: nt, Value: value, Secret: secret})
+		secret = false
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading file")
+	}
+	return configs, nil
+}
+
+// WriteFile writes config vars in the given format, sorted by name and tag.
+func WriteFile(w io.Writer, configs []FileConfig, format string) error {
+	configs = append([]FileConfig(nil), configs...)
+	sort.Slice(configs, func(i, j int) bool {
+		return JoinName(configs[i].NameTag) < JoinName(configs[j].NameTag)
+	})
+
+	switch format {
+	case FormatEnv:
+		var buf bytes.Buffer
+		for _, c := range configs {
+			if c.Secret {
+				fmt.Fprintln(&buf, envSecretMarker)
+			}
+			fmt.Fprintf(&buf, "%s=%s\n", JoinName(c.NameTag), quoteEnv(c.Value))
+		}
+		_, err := w.Write(buf.Bytes())
+		return errors.Wrap(err, "writing file")
+	case FormatYAML:
+		f := yamlFile{Configs: []yamlConfig{}}
+		for _, c := range configs {
+			f.Configs = append(f.Configs, yamlConfig{
+				Name:   c.Name,
+				Tag:    c.Tag,
+				Value:  c.Value,
+				Secret: c.Secret,
+			})
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(f); err != nil {
+			return errors.Wrap(err, "encoding yaml")
+		}
+		return errors.Wrap(enc.Close(), "encoding yaml")
+	default:
+		return errors.Errorf("unknown format %q, expected %s or %s", format, FormatEnv, FormatYAML)
+	}
+}
+
+// quoteEnv quotes a .env value if it would not be read back as is.
+func quoteEnv(value string) string {
+	if value == strings.TrimSpace(value) && !strings.ContainsAny(value, "\"'\n\r\\") {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,53 @@
+package configs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile(t *testing.T) {
+	configs := []FileConfig{
+		{NameTag: NameTag{Name: "db/url", Tag: "prod"}, Value: "postgres://prod"},
+		{NameTag: NameTag{Name: "api_key"}, Value: "s3cr3t", Secret: true},
+		{NameTag: NameTag{Name: "greeting"}, Value: " hello \"world\"\n"},
+		{NameTag: NameTag{Name: "empty"}},
+	}
+
+	for _, format := range []string{FormatEnv, FormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			var assert = require.New(t)
+
+			var buf bytes.Buffer
+			assert.NoError(WriteFile(&buf, configs, format))
+			got, err := ReadFile(&buf, format)
+			assert.NoError(err)
+			assert.ElementsMatch(configs, got)
+		})
+	}
+
+	t.Run("env", func(t *testing.T) {
+		var assert = require.New(t)
+
+		got, err := ReadFile(strings.NewReader(`
+# A comment
+export foo=bar # not a comment
+baz:dev = 'single quoted'
+# secret
+password="p\tw"
+`), FormatEnv)
+		assert.NoError(err)
+		assert.Equal([]FileConfig{
+			{NameTag: NameTag{Name: "foo"}, Value: "bar # not a comment"},
+			{NameTag: NameTag{Name: "baz", Tag: "dev"}, Value: "single quoted"},
+			{NameTag: NameTag{Name: "password"}, Value: "p\tw", Secret: true},
+		}, got)
+
+		_, err = ReadFile(strings.NewReader("foo"), FormatEnv)
+		assert.EqualError(err, "line 1: expected name=value")
+		_, err = ReadFile(strings.NewReader("a:b:c=d"), FormatEnv)
+		assert.Error(err)
+	})
+}
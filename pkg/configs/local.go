@@ -0,0 +1,71 @@
+package configs
+
+import (
+	"io/ioutil"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// LocalFile is the name of the files that define config variables
+// for tasks that are run locally.
+//
+// It maps config names, optionally with a tag, to their values:
+//
+//	db/url: postgres://localhost/db
+//	db/url:prod: postgres://prod/db
+//	api_key:
+//	  value: s3cr3t
+//	  secret: true
+const LocalFile = "airplane.configs.yaml"
+
+// localValue is the value of a config variable in a local file.
+type localValue struct {
+	Value  string `yaml:"value"`
+	Secret bool   `yaml:"secret"`
+}
+
+var _ yaml.Unmarshaler = &localValue{}
+
+// UnmarshalYAML allows setting a value as a string, or as a mapping
+// to mark it as a secret.
+func (v *localValue) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&v.Value)
+	}
+	type value localValue
+	return node.Decode((*value)(v))
+}
+
+// ReadLocal reads the config variables of the given local files, keyed
+// by name and tag as joined by JoinName.
+//
+// Files are read in order, later files override the values of earlier ones.
+func ReadLocal(paths ...string) (map[string]api.Config, error) {
+	configs := map[string]api.Config{}
+	for _, path := range paths {
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", path)
+		}
+
+		var values map[string]localValue
+		if err := yaml.Unmarshal(buf, &values); err != nil {
+			return nil, errors.Wrapf(err, "decoding %s", path)
+		}
+		for name, v := range values {
+			nt, err := ParseName(name)
+			if err != nil || nt.Name == "" {
+				return nil, errors.Errorf("%s: invalid config name %q - expected my_config or my_config:tag", path, name)
+			}
+			configs[JoinName(nt)] = api.Config{
+				Name:     nt.Name,
+				Tag:      nt.Tag,
+				Value:    v.Value,
+				IsSecret: v.Secret,
+			}
+		}
+	}
+	return configs, nil
+}
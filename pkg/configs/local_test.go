@@ -0,0 +1,35 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLocal(t *testing.T) {
+	var assert = require.New(t)
+	dir := t.TempDir()
+
+	root := filepath.Join(dir, LocalFile)
+	assert.NoError(os.WriteFile(root, []byte(`
+db/url: postgres://localhost/db
+db/url:prod: postgres://prod/db
+api_key:
+  value: s3cr3t
+  secret: true
+`), 0600))
+	nested := filepath.Join(dir, "nested", LocalFile)
+	assert.NoError(os.MkdirAll(filepath.Dir(nested), 0755))
+	assert.NoError(os.WriteFile(nested, []byte(`db/url: postgres://nested/db`), 0600))
+
+	configs, err := ReadLocal(root, nested)
+	assert.NoError(err)
+	assert.Equal(map[string]api.Config{
+		"db/url":      {Name: "db/url", Value: "postgres://nested/db"},
+		"db/url:prod": {Name: "db/url", Tag: "prod", Value: "postgres://prod/db"},
+		"api_key":     {Name: "api_key", Value: "s3cr3t", IsSecret: true},
+	}, configs)
+}
@@ -5,7 +5,8 @@ import (
 	"fmt"
 	"os"
 	"reflect"
-	"regexp"
+	"strings"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/airplanedev/cli/pkg/api"
@@ -16,27 +17,50 @@ import (
 
 // CLI parses a list of flags as Airplane parameters and returns the values.
 //
+// Flags override the given values, such as those read with ReadValues. If there
+// are neither flags nor values, the user is prompted for them instead. Either way,
+// the values are checked with Validate.
+//
+// Files of upload parameters, passed as @path, are turned into values with upload
+// once the values are valid.
+//
 // A flag.ErrHelp error will be returned if a -h or --help was provided, in which case
 // this function will print out help text on how to pass this task's parameters as flags.
-func CLI(args []string, client *api.Client, task api.Task) (api.Values, error) {
-	values := api.Values{}
+func CLI(args []string, task api.Task, values api.Values, upload UploadFunc) (api.Values, error) {
+	if values == nil {
+		values = api.Values{}
+	}
 
-	if len(args) > 0 {
+	if len(args) > 0 || len(values) > 0 {
 		// If args have been passed in, parse them as flags
-		set := flagset(task, values)
-		if err := set.Parse(args); err != nil {
+		if err := Flags(args, task, values); err != nil {
 			return nil, err
 		}
 	} else {
 		// Otherwise, try to prompt for parameters
-		if err := promptForParamValues(client, task, values); err != nil {
+		if err := promptForParamValues(task, values); err != nil {
 			return nil, err
 		}
 	}
 
+	if err := Validate(task, values); err != nil {
+		return nil, err
+	}
+	if err := Upload(values, upload); err != nil {
+		return nil, err
+	}
 	return values, nil
 }
 
+// Flags parses a list of flags as Airplane parameters, on top of the given values
+// which are updated in place.
+//
+// Unlike CLI, files of upload parameters are not uploaded, see Upload. Like CLI,
+// a flag.ErrHelp error is returned if a -h or --help was provided.
+func Flags(args []string, task api.Task, values api.Values) error {
+	return flagset(task, values).Parse(args)
+}
+
 // Flagset returns a new flagset from the given task parameters.
 func flagset(task api.Task, args api.Values) *flag.FlagSet {
 	var set = flag.NewFlagSet(task.Name, flag.ContinueOnError)
@@ -69,14 +93,17 @@ func flagset(task api.Task, args api.Values) *flag.FlagSet {
 // If there are no parameters, does nothing.
 // If TTY, prompts for parameters and then asks user to confirm.
 // If no TTY, errors.
-func promptForParamValues(client *api.Client, task api.Task, paramValues map[string]interface{}) error {
+func promptForParamValues(task api.Task, paramValues map[string]interface{}) error {
 	if len(task.Parameters) == 0 {
 		return nil
 	}
 
 	if !utils.CanPrompt() {
-		// Error since we have no params and no way to prompt for it
-		// TODO: if all parameters optional (or have defaults), do not error.
+		// Without a way to prompt, the task can only run if all of its
+		// parameters are optional or have defaults.
+		if Validate(task, paramValues) == nil {
+			return nil
+		}
 		logger.Log("Parameters were not specified! Task has %d parameter(s):\n", len(task.Parameters))
 		for _, param := range task.Parameters {
 			var req string
@@ -90,8 +117,14 @@ func promptForParamValues(client *api.Client, task api.Task, paramValues map[str
 	}
 
 	for _, param := range task.Parameters {
-		if param.Type == api.TypeUpload {
-			logger.Log(logger.Yellow("Skipping %s - uploads are not supported in CLI", param.Name))
+		if len(param.Constraints.Options) > 0 {
+			value, err := promptForOption(param)
+			if err != nil {
+				return err
+			}
+			if value != nil {
+				paramValues[param.Slug] = value
+			}
 			continue
 		}
 
@@ -113,6 +146,10 @@ func promptForParamValues(client *api.Client, task api.Task, paramValues map[str
 		if err := survey.AskOne(prompt, &inputValue, opts...); err != nil {
 			return errors.Wrap(err, "asking prompt for param")
 		}
+		if param.Type == api.TypeDate || param.Type == api.TypeDatetime {
+			// Already checked by validateInput.
+			inputValue, _ = NormalizeTime(param, inputValue, time.Now())
+		}
 
 		value, err := ParseInput(param, inputValue)
 		if err != nil {
@@ -137,13 +174,31 @@ func promptForParamValues(client *api.Client, task api.Task, paramValues map[str
 	return nil
 }
 
-// promptForParam returns a survey.Prompt matching the param type
+// promptForParam returns a survey.Prompt matching the param type and component
 func promptForParam(param api.Parameter) (survey.Prompt, error) {
-	message := fmt.Sprintf("%s %s:", param.Name, logger.Gray("(--%s)", param.Slug))
+	message := promptMessage(param)
 	defaultValue, err := APIValueToInput(param, param.Default)
 	if err != nil {
 		return nil, err
 	}
+
+	switch param.Component {
+	case api.ComponentTextarea, api.ComponentEditorSQL:
+		// Multi-line values are edited in $EDITOR, with the default as a starting point.
+		fileName := "*.txt"
+		if param.Component == api.ComponentEditorSQL {
+			fileName = "*.sql"
+		}
+		return editor{&survey.Editor{
+			Message:       message,
+			Help:          param.Desc,
+			Default:       defaultValue,
+			AppendDefault: true,
+			HideDefault:   true,
+			FileName:      fileName,
+		}}, nil
+	}
+
 	switch param.Type {
 	case api.TypeBoolean:
 		var dv interface{}
@@ -158,6 +213,16 @@ func promptForParam(param api.Parameter) (survey.Prompt, error) {
 			Options: []string{YesString, NoString},
 			Default: dv,
 		}, nil
+	case api.TypeDate, api.TypeDatetime:
+		help := timeHint(param)
+		if param.Desc != "" {
+			help = param.Desc + "\n" + help
+		}
+		return &survey.Input{
+			Message: message,
+			Help:    help,
+			Default: defaultValue,
+		}, nil
 	default:
 		return &survey.Input{
 			Message: message,
@@ -167,6 +232,68 @@ func promptForParam(param api.Parameter) (survey.Prompt, error) {
 	}
 }
 
+// editor is a survey.Editor that trims the newline editors add at the end of files.
+type editor struct {
+	*survey.Editor
+}
+
+func (e editor) Prompt(config *survey.PromptConfig) (interface{}, error) {
+	ans, err := e.Editor.Prompt(config)
+	if s, ok := ans.(string); ok {
+		ans = strings.TrimRight(s, "\r\n")
+	}
+	return ans, err
+}
+
+// promptMessage returns the message of the prompt for param.
+func promptMessage(param api.Parameter) string {
+	if param.Type == api.TypeUpload {
+		return fmt.Sprintf("%s %s:", param.Name, logger.Gray("(--%s @path/to/file)", param.Slug))
+	}
+	return fmt.Sprintf("%s %s:", param.Name, logger.Gray("(--%s)", param.Slug))
+}
+
+// noOption is the choice of an optional parameter's select prompt that leaves it unset.
+const noOption = "(none)"
+
+// promptForOption prompts for one of the options of param by its label,
+// and returns the option's value.
+func promptForOption(param api.Parameter) (interface{}, error) {
+	var labels []string
+	values := map[string]interface{}{}
+	if param.Constraints.Optional && param.Default == nil {
+		labels = append(labels, noOption)
+		values[noOption] = nil
+	}
+	var defaultLabel interface{}
+	for _, o := range param.Constraints.Options {
+		label := o.Label
+		if label == "" {
+			label = fmt.Sprint(o.Value)
+		}
+		if _, ok := values[label]; ok {
+			// Tell options with the same label apart by their value.
+			label = fmt.Sprintf("%s (%v)", label, o.Value)
+		}
+		labels = append(labels, label)
+		values[label] = o.Value
+		if param.Default != nil && reflect.DeepEqual(normalize(o.Value), normalize(param.Default)) {
+			defaultLabel = label
+		}
+	}
+
+	var label string
+	if err := survey.AskOne(&survey.Select{
+		Message: promptMessage(param),
+		Help:    param.Desc,
+		Options: labels,
+		Default: defaultLabel,
+	}, &label, survey.WithStdio(os.Stdin, os.Stderr, os.Stderr)); err != nil {
+		return nil, errors.Wrap(err, "asking prompt for param")
+	}
+	return values[label], nil
+}
+
 // validateInput returns a survey.Validator to perform rudimentary checks on CLI input
 func validateInput(param api.Parameter) func(interface{}) error {
 	return func(ans interface{}) error {
@@ -179,6 +306,10 @@ func validateInput(param api.Parameter) func(interface{}) error {
 		default:
 			return errors.Errorf("unexpected answer of type %s", reflect.TypeOf(a).Name())
 		}
+		if param.Type == api.TypeDate || param.Type == api.TypeDatetime {
+			_, err := NormalizeTime(param, v, time.Now())
+			return err
+		}
 		return ValidateInput(param, v)
 	}
 }
@@ -190,13 +321,6 @@ func regexValidator(pattern string) func(interface{}) error {
 		if !ok {
 			return errors.New("expected string")
 		}
-		matched, err := regexp.MatchString(pattern, str)
-		if err != nil {
-			return errors.Errorf("errored matching against regex: %s", err)
-		}
-		if !matched {
-			return errors.Errorf("must match regex pattern: %s", pattern)
-		}
-		return nil
+		return matchRegex(pattern, str)
 	}
 }
@@ -0,0 +1,96 @@
+package params
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ReadValues reads parameter values from a JSON or YAML file, or from JSON
+// which is read from stdin if it is "-". At most one of them may be set, and
+// no values are returned if neither is.
+//
+// Values are keyed by parameter slug, and are given either as values of their
+// type or as strings like flags are, e.g. @path for uploads. They are checked
+// with ValidateInput, and all invalid values are reported at once.
+func ReadValues(file, jsonValues string, task api.Task) (api.Values, error) {
+	var buf []byte
+	var source string
+	var err error
+	switch {
+	case file != "" && jsonValues != "":
+		return nil, errors.New("only one of --params-file and --params-json can be set")
+	case file != "":
+		source = file
+		if buf, err = ioutil.ReadFile(file); err != nil {
+			return nil, errors.Wrapf(err, "reading %s", file)
+		}
+	case jsonValues == "-":
+		source = "stdin"
+		if buf, err = ioutil.ReadAll(os.Stdin); err != nil {
+			return nil, errors.Wrap(err, "reading stdin")
+		}
+	case jsonValues != "":
+		source = "--params-json"
+		buf = []byte(jsonValues)
+	default:
+		return nil, nil
+	}
+
+	// JSON is valid YAML, so both are decoded as YAML. Scalars are
+	// kept as nodes to read them as they were written.
+	var nodes map[string]yaml.Node
+	if err := yaml.Unmarshal(buf, &nodes); err != nil {
+		return nil, errors.Wrapf(err, "decoding parameters from %s", source)
+	}
+
+	bySlug := map[string]api.Parameter{}
+	for _, p := range task.Parameters {
+		bySlug[p.Slug] = p
+	}
+	slugs := make([]string, 0, len(nodes))
+	for slug := range nodes {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	values := api.Values{}
+	var problems []string
+	for _, slug := range slugs {
+		node := nodes[slug]
+		p, ok := bySlug[slug]
+		if !ok {
+			problems = append(problems, slug+": unknown parameter")
+			continue
+		}
+		if node.Kind != yaml.ScalarNode {
+			problems = append(problems, slug+": expected a single value")
+			continue
+		}
+		in := node.Value
+		if node.Tag == "!!null" {
+			in = ""
+		}
+		if err := ValidateInput(p, in); err != nil {
+			problems = append(problems, slug+": "+err.Error())
+			continue
+		}
+		v, err := ParseInput(p, in)
+		if err != nil {
+			problems = append(problems, slug+": "+err.Error())
+			continue
+		}
+		if v != nil {
+			values[slug] = v
+		}
+	}
+	if len(problems) > 0 {
+		return nil, errors.Errorf("invalid parameters in %s:\n  %s", source, strings.Join(problems, "\n  "))
+	}
+	return values, nil
+}
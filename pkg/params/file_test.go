@@ -0,0 +1,96 @@
+package params
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+var fileTask = api.Task{
+	Name: "Greet",
+	Parameters: api.Parameters{
+		{Slug: "name", Type: api.TypeString},
+		{Slug: "count", Type: api.TypeInteger, Constraints: api.Constraints{Optional: true}},
+		{Slug: "loud", Type: api.TypeBoolean, Constraints: api.Constraints{Optional: true}},
+		{Slug: "report", Type: api.TypeUpload, Constraints: api.Constraints{Optional: true}},
+	},
+}
+
+func TestReadValues(t *testing.T) {
+	t.Run("yaml file", func(t *testing.T) {
+		assert := require.New(t)
+
+		file := writeFile(t, "params.yaml", "name: Alice\ncount: 3\nloud: yes\n")
+		values, err := ReadValues(file, "", fileTask)
+		assert.NoError(err)
+		assert.Equal(api.Values{"name": "Alice", "count": 3, "loud": true}, values)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		assert := require.New(t)
+
+		values, err := ReadValues("", `{"name": "Alice", "count": "3", "loud": null}`, fileTask)
+		assert.NoError(err)
+		assert.Equal(api.Values{"name": "Alice", "count": 3}, values)
+	})
+
+	t.Run("no values", func(t *testing.T) {
+		assert := require.New(t)
+
+		values, err := ReadValues("", "", fileTask)
+		assert.NoError(err)
+		assert.Nil(values)
+	})
+
+	t.Run("file and json", func(t *testing.T) {
+		assert := require.New(t)
+
+		file := writeFile(t, "params.yaml", "name: Alice\n")
+		_, err := ReadValues(file, `{"name": "Bob"}`, fileTask)
+		assert.EqualError(err, "only one of --params-file and --params-json can be set")
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ReadValues("", `{"name": ["Alice", "Bob"], "count": "many", "age": 30}`, fileTask)
+		assert.EqualError(err, "invalid parameters in --params-json:\n"+
+			"  age: unknown parameter\n"+
+			"  count: invalid integer\n"+
+			"  name: expected a single value")
+	})
+}
+
+func TestCLIOverridesValues(t *testing.T) {
+	assert := require.New(t)
+
+	dir := t.TempDir()
+	fromFile := writeFile(t, "a.csv", "a")
+	fromFlag := writeFile(t, "b.csv", "b")
+
+	file := writeFile(t, "params.yaml", "name: Alice\ncount: 3\nreport: \"@"+fromFile+"\"\n")
+	values, err := ReadValues(file, "", fileTask)
+	assert.NoError(err)
+
+	var uploaded []string
+	values, err = CLI([]string{"--count", "5", "--report", "@" + fromFlag}, fileTask, values, func(path string) (interface{}, error) {
+		uploaded = append(uploaded, path)
+		return filepath.Join(dir, filepath.Base(path)), nil
+	})
+	assert.NoError(err)
+	assert.Equal(api.Values{"name": "Alice", "count": 5, "report": filepath.Join(dir, "b.csv")}, values)
+	assert.Equal([]string{fromFlag}, uploaded)
+}
+
+// writeFile writes a file with the given name and content to a
+// temporary directory, and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
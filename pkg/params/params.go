@@ -3,6 +3,7 @@
 package params
 
 import (
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -44,18 +45,22 @@ func ValidateInput(param api.Parameter, in string) error {
 		}
 
 	case api.TypeUpload:
-		if in != "" {
-			// TODO(amir): we need to support them with some special
-			// character perhaps `@` like curl?
-			return errors.New("uploads are not supported from the CLI")
+		path, ok := uploadPath(in)
+		if !ok {
+			return errors.New("expected a file path prefixed with @, e.g. @./report.csv")
+		}
+		if info, err := os.Stat(path); err != nil {
+			return errors.Errorf("unable to open file: %s", path)
+		} else if info.IsDir() {
+			return errors.Errorf("expected a file, got a directory: %s", path)
 		}
 
 	case api.TypeDate:
-		if _, err := time.Parse("2006-01-02", in); err != nil {
+		if _, err := time.Parse(DateFormat, in); err != nil {
 			return errors.New("expected to be formatted as '2016-01-02'")
 		}
 	case api.TypeDatetime:
-		if _, err := time.Parse("2006-01-02T15:04:05Z", in); err != nil {
+		if _, err := time.Parse(DatetimeFormat, in); err != nil {
 			return errors.New("expected to be formatted as '2016-01-02T15:04:05Z'")
 		}
 		return nil
@@ -91,11 +96,12 @@ func ParseInput(param api.Parameter, in string) (interface{}, error) {
 		return v, nil
 
 	case api.TypeUpload:
-		// TODO: ideally we read the file input here for API
-		if in != "" {
-			return nil, errors.New("uploads are not supported from the CLI")
+		// Files are uploaded by Upload, once all values are final.
+		if err := ValidateInput(param, in); err != nil {
+			return nil, err
 		}
-		return nil, nil
+		path, _ := uploadPath(in)
+		return uploadFile(path), nil
 
 	case api.TypeConfigVar:
 		return map[string]interface{}{
@@ -0,0 +1,127 @@
+package params
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/pkg/errors"
+)
+
+// Formats of date and datetime values in the API.
+const (
+	DateFormat     = "2006-01-02"
+	DatetimeFormat = "2006-01-02T15:04:05Z"
+)
+
+// localLayouts are the layouts of times entered in local time, tried in order.
+var localLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	DateFormat,
+}
+
+// relativeTime matches times relative to now, e.g. +2d, -3h, in 2 weeks or 5 minutes ago.
+var relativeTime = regexp.MustCompile(`^(in\s+)?([+-]?\d+)\s*([a-z]+)(\s+ago)?$`)
+
+// relativeUnit adds n units to a time. Days and weeks are calendar days, which
+// keep the time of day across daylight saving time changes.
+type relativeUnit func(t time.Time, n int) time.Time
+
+func addDuration(d time.Duration) relativeUnit {
+	return func(t time.Time, n int) time.Time { return t.Add(time.Duration(n) * d) }
+}
+
+func addDays(count int) relativeUnit {
+	return func(t time.Time, n int) time.Time { return t.AddDate(0, 0, n*count) }
+}
+
+var (
+	minutes = addDuration(time.Minute)
+	hours   = addDuration(time.Hour)
+	days    = addDays(1)
+	weeks   = addDays(7)
+)
+
+var relativeUnits = map[string]relativeUnit{
+	"m": minutes, "min": minutes, "mins": minutes, "minute": minutes, "minutes": minutes,
+	"h": hours, "hour": hours, "hours": hours,
+	"d": days, "day": days, "days": days,
+	"w": weeks, "week": weeks, "weeks": weeks,
+}
+
+// timeHint describes the input accepted by NormalizeTime for param.
+func timeHint(param api.Parameter) string {
+	if param.Type == api.TypeDate {
+		return "Enter a date such as 2006-01-02, today, tomorrow or -3d."
+	}
+	return "Enter a local time such as 2006-01-02 15:04, a UTC time such as 2006-01-02T15:04:05Z, now or +2h."
+}
+
+// NormalizeTime converts the input of a date or datetime parameter to its API format.
+//
+// Besides the API format, the input can be a time relative to now, such as now, today,
+// yesterday, tomorrow, +2d or 3 hours ago, or a local time such as 2006-01-02 15:04.
+func NormalizeTime(param api.Parameter, in string, now time.Time) (string, error) {
+	in = strings.TrimSpace(in)
+	if in == "" {
+		return "", nil
+	}
+
+	t, ok := parseTime(strings.ToLower(in), now)
+	if !ok {
+		if param.Type == api.TypeDate {
+			return "", errors.New("expected a date such as 2006-01-02, today or -3d")
+		}
+		return "", errors.New("expected a time such as 2006-01-02 15:04, 2006-01-02T15:04:05Z, now or +2h")
+	}
+
+	if param.Type == api.TypeDate {
+		return t.Format(DateFormat), nil
+	}
+	return t.UTC().Format(DatetimeFormat), nil
+}
+
+// parseTime parses a lowercase time, relative to now or in its location.
+func parseTime(in string, now time.Time) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch in {
+	case "now":
+		return now, true
+	case "today":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	}
+
+	if m := relativeTime.FindStringSubmatch(in); m != nil {
+		add, ok := relativeUnits[m[3]]
+		if !ok || (m[1] != "" && m[4] != "") {
+			return time.Time{}, false
+		}
+		n, err := strconv.Atoi(m[2])
+		if err != nil {
+			return time.Time{}, false
+		}
+		if m[4] != "" {
+			n = -n
+		}
+		return add(now, n), true
+	}
+
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(in)); err == nil {
+		return t, true
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, strings.ToUpper(in), now.Location()); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,61 @@
+package params
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTime(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// Daylight saving time starts the next day.
+	now := time.Date(2022, 3, 12, 15, 4, 5, 0, loc)
+
+	date := api.Parameter{Slug: "day", Type: api.TypeDate}
+	datetime := api.Parameter{Slug: "at", Type: api.TypeDatetime}
+
+	for _, test := range []struct {
+		param api.Parameter
+		in    string
+		out   string
+		err   bool
+	}{
+		{param: datetime, in: "now", out: "2022-03-12T20:04:05Z"},
+		{param: date, in: "today", out: "2022-03-12"},
+		{param: date, in: "Yesterday", out: "2022-03-11"},
+		{param: date, in: "tomorrow", out: "2022-03-13"},
+		{param: date, in: "+2d", out: "2022-03-14"},
+		{param: date, in: "-1w", out: "2022-03-05"},
+		{param: datetime, in: "+1d", out: "2022-03-13T19:04:05Z"},
+		{param: datetime, in: "in 2 weeks", out: "2022-03-26T19:04:05Z"},
+		{param: datetime, in: "3 hours ago", out: "2022-03-12T17:04:05Z"},
+		{param: datetime, in: "-30m", out: "2022-03-12T19:34:05Z"},
+		{param: datetime, in: "2022-01-02 09:30", out: "2022-01-02T14:30:00Z"},
+		{param: datetime, in: "2022-07-02T09:30:00", out: "2022-07-02T13:30:00Z"},
+		{param: datetime, in: "2022-01-02T09:30:00Z", out: "2022-01-02T09:30:00Z"},
+		{param: datetime, in: "2022-01-02T09:30:00+01:00", out: "2022-01-02T08:30:00Z"},
+		{param: date, in: "2022-01-02", out: "2022-01-02"},
+		{param: date, in: "2022-01-02 23:30", out: "2022-01-02"},
+		{param: datetime, in: " ", out: ""},
+		{param: date, in: "soon", err: true},
+		{param: datetime, in: "+2 fortnights", err: true},
+		{param: datetime, in: "in 2 days ago", err: true},
+		{param: datetime, in: "2022-13-01", err: true},
+	} {
+		t.Run(string(test.param.Type)+" "+test.in, func(t *testing.T) {
+			assert := require.New(t)
+
+			out, err := NormalizeTime(test.param, test.in, now)
+			if test.err {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(test.out, out)
+		})
+	}
+}
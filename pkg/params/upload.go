@@ -0,0 +1,118 @@
+package params
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+)
+
+// UploadFunc turns the local file at path into the value of an upload parameter.
+type UploadFunc func(path string) (interface{}, error)
+
+// uploadFile is the value of an upload parameter before its file is uploaded,
+// which is the path of the file.
+//
+// Files are only uploaded by Upload once all values are final and valid, so that
+// files that are overridden, or that are passed to a command that fails, are not.
+type uploadFile string
+
+// Upload turns the files of upload parameters in values into their API values
+// with upload.
+func Upload(values api.Values, upload UploadFunc) error {
+	for slug, v := range values {
+		path, ok := v.(uploadFile)
+		if !ok {
+			continue
+		}
+		value, err := upload(string(path))
+		if err != nil {
+			return err
+		}
+		values[slug] = value
+	}
+	return nil
+}
+
+// uploadPath returns the file path of an upload input, which is
+// a path prefixed with @ (e.g. @./report.csv).
+func uploadPath(in string) (string, bool) {
+	if !strings.HasPrefix(in, "@") || len(in) == 1 {
+		return "", false
+	}
+	return strings.TrimPrefix(in, "@"), true
+}
+
+// uploadValue returns the API value of an upload parameter.
+func uploadValue(id, url string) map[string]interface{} {
+	return map[string]interface{}{
+		"__airplaneType": "upload",
+		"id":             id,
+		"url":            url,
+	}
+}
+
+// APIUploader returns an UploadFunc that uploads files to Airplane.
+func APIUploader(ctx context.Context, client *api.Client) UploadFunc {
+	return func(path string) (interface{}, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "opening file")
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil {
+			return nil, errors.Wrap(err, "stat on file")
+		}
+		sizeBytes := int(info.Size())
+
+		logger.Log("Uploading %s (%s)...", path, humanize.Bytes(uint64(sizeBytes)))
+
+		upload, err := client.CreateUpload(ctx, api.CreateUploadRequest{
+			FileName:  filepath.Base(path),
+			SizeBytes: sizeBytes,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "creating upload")
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "PUT", upload.WriteOnlyURL, f)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating GCS upload request")
+		}
+		req.ContentLength = info.Size()
+		req.Header.Add("X-Goog-Content-Length-Range", fmt.Sprintf("0,%d", sizeBytes))
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, errors.Wrap(err, "uploading to GCS")
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			return nil, errors.Errorf("uploading to GCS: %s", resp.Status)
+		}
+
+		logger.Debug("Upload complete: %s", upload.Upload.URL)
+		return uploadValue(upload.Upload.ID, upload.Upload.URL), nil
+	}
+}
+
+// LocalUpload is an UploadFunc that references the file on the local
+// filesystem instead of uploading it, for tasks that run locally.
+func LocalUpload(path string) (interface{}, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "absolute path of %s", path)
+	}
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}
+	return uploadValue("", u.String()), nil
+}
@@ -0,0 +1,103 @@
+package params
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/pkg/errors"
+)
+
+// Validate checks values against the constraints of the task's parameters,
+// whether they were passed as flags or entered at prompts. Parameters without
+// a value are set to their default, if they have one.
+//
+// Missing required parameters, regex mismatches and values that are not one of
+// the parameter's options are all reported at once.
+func Validate(task api.Task, values api.Values) error {
+	var problems []string
+	for _, p := range task.Parameters {
+		v := values[p.Slug]
+		if isEmpty(v) {
+			if p.Default == nil {
+				if !p.Constraints.Optional {
+					problems = append(problems, p.Slug+": required")
+				}
+				continue
+			}
+			v = p.Default
+			values[p.Slug] = v
+		}
+
+		if s, ok := v.(string); ok && p.Type == api.TypeString && p.Constraints.Regex != "" {
+			if err := matchRegex(p.Constraints.Regex, s); err != nil {
+				problems = append(problems, p.Slug+": "+err.Error())
+			}
+		}
+		if len(p.Constraints.Options) > 0 && !hasOption(p, v) {
+			problems = append(problems, fmt.Sprintf("%s: %v is not an option, expected one of %s", p.Slug, v, optionLabels(p)))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.Errorf("invalid parameters:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// isEmpty reports whether v is not a value, as when a flag is set to "".
+func isEmpty(v interface{}) bool {
+	return v == nil || v == ""
+}
+
+// matchRegex returns an error if s does not match pattern.
+func matchRegex(pattern, s string) error {
+	matched, err := regexp.MatchString(pattern, s)
+	if err != nil {
+		return errors.Errorf("errored matching against regex: %s", err)
+	}
+	if !matched {
+		return errors.Errorf("must match regex pattern: %s", pattern)
+	}
+	return nil
+}
+
+// hasOption reports whether v is the value of one of the options of p.
+func hasOption(p api.Parameter, v interface{}) bool {
+	for _, o := range p.Constraints.Options {
+		if reflect.DeepEqual(normalize(o.Value), normalize(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize converts numbers to float64, since values parsed from flags are
+// ints while those decoded from JSON are float64s.
+func normalize(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float32:
+		return float64(n)
+	}
+	return v
+}
+
+// optionLabels describes the options of p, with their values when they
+// differ from their labels, e.g. "Small (s), Large (l)".
+func optionLabels(p api.Parameter) string {
+	labels := make([]string, len(p.Constraints.Options))
+	for i, o := range p.Constraints.Options {
+		value := fmt.Sprint(o.Value)
+		if o.Label == "" || o.Label == value {
+			labels[i] = value
+		} else {
+			labels[i] = fmt.Sprintf("%s (%s)", o.Label, value)
+		}
+	}
+	return strings.Join(labels, ", ")
+}
@@ -0,0 +1,65 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	task := api.Task{
+		Parameters: api.Parameters{
+			{Slug: "name", Type: api.TypeString, Constraints: api.Constraints{Regex: "^[a-z]+$"}},
+			{Slug: "greeting", Type: api.TypeString, Default: "hello"},
+			{Slug: "note", Type: api.TypeString, Constraints: api.Constraints{Optional: true}},
+			{Slug: "size", Type: api.TypeString, Constraints: api.Constraints{
+				Optional: true,
+				Options:  []api.ConstraintOption{{Label: "Small", Value: "s"}, {Label: "Large", Value: "l"}},
+			}},
+		},
+	}
+
+	t.Run("fill in defaults", func(t *testing.T) {
+		assert := require.New(t)
+
+		values := api.Values{"name": "alice", "size": "l"}
+		assert.NoError(Validate(task, values))
+		assert.Equal(api.Values{"name": "alice", "greeting": "hello", "size": "l"}, values)
+	})
+
+	t.Run("report every problem", func(t *testing.T) {
+		assert := require.New(t)
+
+		err := Validate(task, api.Values{"greeting": "", "size": "m"})
+		assert.EqualError(err, "invalid parameters:\n"+
+			"  name: required\n"+
+			"  size: m is not an option, expected one of Small (s), Large (l)")
+	})
+
+	t.Run("regex mismatch", func(t *testing.T) {
+		assert := require.New(t)
+
+		err := Validate(task, api.Values{"name": "Alice"})
+		assert.EqualError(err, "invalid parameters:\n  name: must match regex pattern: ^[a-z]+$")
+	})
+}
+
+func TestHasOption(t *testing.T) {
+	assert := require.New(t)
+
+	// Values parsed from flags are ints, while options decoded from JSON are float64s.
+	p := api.Parameter{Slug: "count", Type: api.TypeInteger, Constraints: api.Constraints{
+		Options: []api.ConstraintOption{{Value: float64(1)}, {Value: float64(2)}},
+	}}
+	assert.True(hasOption(p, 2))
+	assert.True(hasOption(p, int64(1)))
+	assert.True(hasOption(p, float64(2)))
+	assert.False(hasOption(p, 3))
+	assert.False(hasOption(p, "2"))
+
+	p.Constraints.Options = []api.ConstraintOption{{Value: 1}, {Value: 2}}
+	assert.True(hasOption(p, float64(1)))
+	assert.True(hasOption(p, float32(2)))
+	assert.False(hasOption(p, 1.5))
+}
@@ -0,0 +1,80 @@
+package print
+
+import (
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/ojson"
+)
+
+// Types of run events.
+const (
+	EventLog     = "log"
+	EventStatus  = "status"
+	EventOutputs = "outputs"
+)
+
+// Event is something that happened during a run, such as a log line, a change
+// of status or its outputs.
+//
+// Commands that follow runs print events as newline-delimited JSON when the
+// output format is JSON, so that they can be parsed by other programs.
+type Event struct {
+	Type  string    `json:"type"`
+	Time  time.Time `json:"ts"`
+	RunID string    `json:"runID,omitempty"`
+
+	// Set on log events.
+	Level api.LogLevel `json:"level,omitempty"`
+	Text  string       `json:"text,omitempty"`
+
+	// Set on status events.
+	Status api.RunStatus `json:"status,omitempty"`
+	// Error is why a local run failed, if it did.
+	Error string `json:"error,omitempty"`
+
+	// Set on outputs events.
+	Outputs *ojson.Value `json:"outputs,omitempty"`
+}
+
+// Events reports whether run events should be printed, instead of
+// human-readable logs.
+func Events() bool {
+	_, ok := DefaultFormatter.(*JSON)
+	return ok
+}
+
+// PrintEvent prints e as a single line of JSON. Its time defaults to now.
+func PrintEvent(e Event) {
+	j, ok := DefaultFormatter.(*JSON)
+	if !ok {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	j.Encode(e)
+}
+
+// LogEvents prints a batch of run logs as log events.
+func LogEvents(runID string, logs []api.LogItem) {
+	for _, l := range logs {
+		PrintEvent(Event{
+			Type:  EventLog,
+			Time:  l.Timestamp,
+			RunID: runID,
+			Level: l.Level,
+			Text:  l.Text,
+		})
+	}
+}
+
+// OutputsEvent prints the outputs of a run as an outputs event.
+func OutputsEvent(runID string, outputs api.Outputs) {
+	o := ojson.Value(outputs)
+	PrintEvent(Event{
+		Type:    EventOutputs,
+		RunID:   runID,
+		Outputs: &o,
+	})
+}
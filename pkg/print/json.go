@@ -59,3 +59,17 @@ func (j *JSON) outputs(outputs api.Outputs) {
 func (j *JSON) config(config api.Config) {
 	j.enc.Encode(config)
 }
+
+// Configs implementation.
+func (j *JSON) configs(configs []api.Config) {
+	j.enc.Encode(configs)
+}
+
+// Logs implementation.
+//
+// Each log item is printed on its own line.
+func (j *JSON) logs(logs []api.LogItem) {
+	for _, l := range logs {
+		j.enc.Encode(l)
+	}
+}
@@ -21,6 +21,8 @@ type Formatter interface {
 	run(api.Run)
 	outputs(api.Outputs)
 	config(api.Config)
+	configs([]api.Config)
+	logs([]api.LogItem)
 }
 
 // APIKeys prints one or more API keys.
@@ -58,6 +60,19 @@ func Config(config api.Config) {
 	DefaultFormatter.config(config)
 }
 
+// Configs prints one or more config vars.
+func Configs(configs []api.Config) {
+	DefaultFormatter.configs(configs)
+}
+
+// Logs prints a batch of run logs.
+//
+// It may be called repeatedly while following a run, so formatters
+// print each batch in a way that can be appended to the previous one.
+func Logs(logs []api.LogItem) {
+	DefaultFormatter.logs(logs)
+}
+
 // Print outputs obj based on DefaultFormatter
 // If JSON or YAML, uses that formatter to encode obj
 // Otherwise, calls defaultPrintFunc to render the obj
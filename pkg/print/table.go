@@ -288,3 +288,40 @@ func (t Table) config(config api.Config) {
 	}
 	fmt.Fprintln(os.Stdout, valueStr)
 }
+
+// Configs implementation.
+func (t Table) configs(configs []api.Config) {
+	tw := tablewriter.NewWriter(os.Stdout)
+	tw.SetBorder(false)
+	tw.SetHeader([]string{"name", "tag", "value"})
+
+	for _, c := range configs {
+		value := c.Value
+		if c.IsSecret {
+			value = logger.Gray("<secret value hidden>")
+		}
+		tw.Append([]string{c.Name, c.Tag, value})
+	}
+
+	tw.Render()
+}
+
+// logs prints the logs as plain lines.
+func (t Table) logs(logs []api.LogItem) {
+	for _, l := range logs {
+		fmt.Fprintln(os.Stdout, LogText(l))
+	}
+}
+
+// LogText formats a log line for the terminal.
+//
+// Agent logs are de-emphasized and lose their prefix, while user logs
+// are left alone so they can apply their own colors.
+func LogText(l api.LogItem) string {
+	const agentPrefix = "[agent]"
+
+	if strings.HasPrefix(l.Text, agentPrefix) {
+		return logger.Gray(strings.TrimLeft(strings.TrimPrefix(l.Text, agentPrefix), " "))
+	}
+	return fmt.Sprintf("[%s] %s", logger.Gray("log"), l.Text)
+}
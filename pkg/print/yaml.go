@@ -1,6 +1,7 @@
 package print
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/airplanedev/cli/pkg/api"
@@ -53,3 +54,19 @@ func (YAML) outputs(outputs api.Outputs) {
 func (YAML) config(config api.Config) {
 	yaml.NewEncoder(os.Stdout).Encode(config)
 }
+
+// Configs implementation.
+func (YAML) configs(configs []api.Config) {
+	yaml.NewEncoder(os.Stdout).Encode(configs)
+}
+
+// Logs implementation.
+//
+// Each log is printed as its own document, so that batches of logs
+// form a single stream of YAML documents.
+func (YAML) logs(logs []api.LogItem) {
+	for _, l := range logs {
+		fmt.Fprintln(os.Stdout, "---")
+		yaml.NewEncoder(os.Stdout).Encode(l)
+	}
+}
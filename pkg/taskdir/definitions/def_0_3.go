@@ -4,12 +4,14 @@ import (
 	"context"
 	_ "embed"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path"
 
 	"github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/lib/pkg/build"
 	"github.com/goccy/go-yaml"
+	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 	"github.com/xeipuuv/gojsonschema"
 )
@@ -39,6 +41,7 @@ type Definition_0_3 struct {
 
 type taskKind_0_3 interface {
 	fillInUpdateTaskRequest(context.Context, *api.Client, *api.UpdateTaskRequest) error
+	hydrateFromTask(t *api.Task, resourceNames map[string]string) error
 	upgradeJST() error
 	getKindOptions() (build.KindOptions, error)
 	getEntrypoint() (string, error)
@@ -49,10 +52,10 @@ type taskKind_0_3 interface {
 var _ taskKind_0_3 = &ImageDefinition_0_3{}
 
 type ImageDefinition_0_3 struct {
-	Image   string      `json:"image"`
-	Command []string    `json:"command"`
-	Root    string      `json:"root,omitempty"`
-	Env     api.TaskEnv `json:"env,omitempty"`
+	Image   string            `json:"image"`
+	Command []string          `json:"command"`
+	Root    string            `json:"root,omitempty"`
+	Env     EnvDefinition_0_3 `json:"env,omitempty"`
 }
 
 func (d *ImageDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, client *api.Client, req *api.UpdateTaskRequest) error {
@@ -61,6 +64,15 @@ func (d *ImageDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, clien
 	return nil
 }
 
+func (d *ImageDefinition_0_3) hydrateFromTask(t *api.Task, resourceNames map[string]string) error {
+	if t.Image != nil {
+		d.Image = *t.Image
+	}
+	d.Command = t.Command
+	d.Env = EnvDefinition_0_3(t.Env)
+	return nil
+}
+
 func (d *ImageDefinition_0_3) upgradeJST() error {
 	return nil
 }
@@ -78,7 +90,7 @@ func (d *ImageDefinition_0_3) getRoot() (string, error) {
 }
 
 func (d *ImageDefinition_0_3) getEnv() (api.TaskEnv, error) {
-	return d.Env, nil
+	return api.TaskEnv(d.Env), nil
 }
 
 var _ taskKind_0_3 = &DenoDefinition_0_3{}
@@ -86,9 +98,9 @@ var _ taskKind_0_3 = &DenoDefinition_0_3{}
 type DenoDefinition_0_3 struct {
 	Entrypoint string `json:"entrypoint"`
 	// TODO: default {{JSON.stringify(params)}}
-	Arguments []string    `json:"arguments,omitempty"`
-	Root      string      `json:"root,omitempty"`
-	Env       api.TaskEnv `json:"env,omitempty"`
+	Arguments []string          `json:"arguments,omitempty"`
+	Root      string            `json:"root,omitempty"`
+	Env       EnvDefinition_0_3 `json:"env,omitempty"`
 }
 
 func (d *DenoDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, client *api.Client, req *api.UpdateTaskRequest) error {
@@ -96,6 +108,13 @@ func (d *DenoDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, client
 	return nil
 }
 
+func (d *DenoDefinition_0_3) hydrateFromTask(t *api.Task, resourceNames map[string]string) error {
+	d.Entrypoint = kindOption(t, "entrypoint")
+	d.Arguments = t.Arguments
+	d.Env = EnvDefinition_0_3(t.Env)
+	return nil
+}
+
 func (d *DenoDefinition_0_3) upgradeJST() error {
 	d.Arguments = upgradeArguments(d.Arguments)
 	return nil
@@ -116,21 +135,27 @@ func (d *DenoDefinition_0_3) getRoot() (string, error) {
 }
 
 func (d *DenoDefinition_0_3) getEnv() (api.TaskEnv, error) {
-	return d.Env, nil
+	return api.TaskEnv(d.Env), nil
 }
 
 var _ taskKind_0_3 = &DockerfileDefinition_0_3{}
 
 type DockerfileDefinition_0_3 struct {
-	Dockerfile string      `json:"dockerfile"`
-	Root       string      `json:"root,omitempty"`
-	Env        api.TaskEnv `json:"env,omitempty"`
+	Dockerfile string            `json:"dockerfile"`
+	Root       string            `json:"root,omitempty"`
+	Env        EnvDefinition_0_3 `json:"env,omitempty"`
 }
 
 func (d *DockerfileDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, client *api.Client, req *api.UpdateTaskRequest) error {
 	return nil
 }
 
+func (d *DockerfileDefinition_0_3) hydrateFromTask(t *api.Task, resourceNames map[string]string) error {
+	d.Dockerfile = kindOption(t, "dockerfile")
+	d.Env = EnvDefinition_0_3(t.Env)
+	return nil
+}
+
 func (d *DockerfileDefinition_0_3) upgradeJST() error {
 	return nil
 }
@@ -150,7 +175,7 @@ func (d *DockerfileDefinition_0_3) getRoot() (string, error) {
 }
 
 func (d *DockerfileDefinition_0_3) getEnv() (api.TaskEnv, error) {
-	return d.Env, nil
+	return api.TaskEnv(d.Env), nil
 }
 
 var _ taskKind_0_3 = &GoDefinition_0_3{}
@@ -158,9 +183,9 @@ var _ taskKind_0_3 = &GoDefinition_0_3{}
 type GoDefinition_0_3 struct {
 	Entrypoint string `json:"entrypoint"`
 	// TODO: default {{JSON.stringify(params)}}
-	Arguments []string    `json:"arguments,omitempty"`
-	Root      string      `json:"root,omitempty"`
-	Env       api.TaskEnv `json:"env,omitempty"`
+	Arguments []string          `json:"arguments,omitempty"`
+	Root      string            `json:"root,omitempty"`
+	Env       EnvDefinition_0_3 `json:"env,omitempty"`
 }
 
 func (d *GoDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, client *api.Client, req *api.UpdateTaskRequest) error {
@@ -168,6 +193,13 @@ func (d *GoDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, client *
 	return nil
 }
 
+func (d *GoDefinition_0_3) hydrateFromTask(t *api.Task, resourceNames map[string]string) error {
+	d.Entrypoint = kindOption(t, "entrypoint")
+	d.Arguments = t.Arguments
+	d.Env = EnvDefinition_0_3(t.Env)
+	return nil
+}
+
 func (d *GoDefinition_0_3) upgradeJST() error {
 	d.Arguments = upgradeArguments(d.Arguments)
 	return nil
@@ -188,7 +220,7 @@ func (d *GoDefinition_0_3) getRoot() (string, error) {
 }
 
 func (d *GoDefinition_0_3) getEnv() (api.TaskEnv, error) {
-	return d.Env, nil
+	return api.TaskEnv(d.Env), nil
 }
 
 var _ taskKind_0_3 = &NodeDefinition_0_3{}
@@ -197,9 +229,9 @@ type NodeDefinition_0_3 struct {
 	Entrypoint  string `json:"entrypoint"`
 	NodeVersion string `json:"nodeVersion"`
 	// TODO: default {{JSON.stringify(params)}}
-	Arguments []string    `json:"arguments,omitempty"`
-	Root      string      `json:"root,omitempty"`
-	Env       api.TaskEnv `json:"env,omitempty"`
+	Arguments []string          `json:"arguments,omitempty"`
+	Root      string            `json:"root,omitempty"`
+	Env       EnvDefinition_0_3 `json:"env,omitempty"`
 }
 
 func (d *NodeDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, client *api.Client, req *api.UpdateTaskRequest) error {
@@ -207,6 +239,14 @@ func (d *NodeDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, client
 	return nil
 }
 
+func (d *NodeDefinition_0_3) hydrateFromTask(t *api.Task, resourceNames map[string]string) error {
+	d.Entrypoint = kindOption(t, "entrypoint")
+	d.NodeVersion = kindOption(t, "nodeVersion")
+	d.Arguments = t.Arguments
+	d.Env = EnvDefinition_0_3(t.Env)
+	return nil
+}
+
 func (d *NodeDefinition_0_3) upgradeJST() error {
 	d.Arguments = upgradeArguments(d.Arguments)
 	return nil
@@ -228,7 +268,7 @@ func (d *NodeDefinition_0_3) getRoot() (string, error) {
 }
 
 func (d *NodeDefinition_0_3) getEnv() (api.TaskEnv, error) {
-	return d.Env, nil
+	return api.TaskEnv(d.Env), nil
 }
 
 var _ taskKind_0_3 = &PythonDefinition_0_3{}
@@ -236,9 +276,9 @@ var _ taskKind_0_3 = &PythonDefinition_0_3{}
 type PythonDefinition_0_3 struct {
 	Entrypoint string `json:"entrypoint"`
 	// TODO: default {{JSON.stringify(params)}}
-	Arguments []string    `json:"arguments,omitempty"`
-	Root      string      `json:"root,omitempty"`
-	Env       api.TaskEnv `json:"env,omitempty"`
+	Arguments []string          `json:"arguments,omitempty"`
+	Root      string            `json:"root,omitempty"`
+	Env       EnvDefinition_0_3 `json:"env,omitempty"`
 }
 
 func (d *PythonDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, client *api.Client, req *api.UpdateTaskRequest) error {
@@ -246,6 +286,13 @@ func (d *PythonDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, clie
 	return nil
 }
 
+func (d *PythonDefinition_0_3) hydrateFromTask(t *api.Task, resourceNames map[string]string) error {
+	d.Entrypoint = kindOption(t, "entrypoint")
+	d.Arguments = t.Arguments
+	d.Env = EnvDefinition_0_3(t.Env)
+	return nil
+}
+
 func (d *PythonDefinition_0_3) upgradeJST() error {
 	d.Arguments = upgradeArguments(d.Arguments)
 	return nil
@@ -266,7 +313,7 @@ func (d *PythonDefinition_0_3) getRoot() (string, error) {
 }
 
 func (d *PythonDefinition_0_3) getEnv() (api.TaskEnv, error) {
-	return d.Env, nil
+	return api.TaskEnv(d.Env), nil
 }
 
 var _ taskKind_0_3 = &ShellDefinition_0_3{}
@@ -274,9 +321,9 @@ var _ taskKind_0_3 = &ShellDefinition_0_3{}
 type ShellDefinition_0_3 struct {
 	Entrypoint string `json:"entrypoint"`
 	// TODO: defaults to PARAM1={{params.param1}} PARAM2{{params.param2}} etc.
-	Arguments []string    `json:"arguments,omitempty"`
-	Root      string      `json:"root,omitempty"`
-	Env       api.TaskEnv `json:"env,omitempty"`
+	Arguments []string          `json:"arguments,omitempty"`
+	Root      string            `json:"root,omitempty"`
+	Env       EnvDefinition_0_3 `json:"env,omitempty"`
 }
 
 func (d *ShellDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, client *api.Client, req *api.UpdateTaskRequest) error {
@@ -284,6 +331,13 @@ func (d *ShellDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, clien
 	return nil
 }
 
+func (d *ShellDefinition_0_3) hydrateFromTask(t *api.Task, resourceNames map[string]string) error {
+	d.Entrypoint = kindOption(t, "entrypoint")
+	d.Arguments = t.Arguments
+	d.Env = EnvDefinition_0_3(t.Env)
+	return nil
+}
+
 func (d *ShellDefinition_0_3) upgradeJST() error {
 	d.Arguments = upgradeArguments(d.Arguments)
 	return nil
@@ -304,7 +358,7 @@ func (d *ShellDefinition_0_3) getRoot() (string, error) {
 }
 
 func (d *ShellDefinition_0_3) getEnv() (api.TaskEnv, error) {
-	return d.Env, nil
+	return api.TaskEnv(d.Env), nil
 }
 
 var _ taskKind_0_3 = &SQLDefinition_0_3{}
@@ -330,6 +384,20 @@ func (d *SQLDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, client
 	return nil
 }
 
+// hydrateFromTask fills in the definition from a task.
+//
+// The query of the task is not stored in the definition, the caller is
+// expected to write it to the entrypoint, which defaults to <slug>.sql.
+func (d *SQLDefinition_0_3) hydrateFromTask(t *api.Task, resourceNames map[string]string) error {
+	d.Resource = resourceNames["db"]
+
+	d.Entrypoint = t.Slug + ".sql"
+	if queryArgs, ok := t.KindOptions["queryArgs"].(map[string]interface{}); ok && len(queryArgs) > 0 {
+		d.Parameters = queryArgs
+	}
+	return nil
+}
+
 func (d *SQLDefinition_0_3) upgradeJST() error {
 	return nil
 }
@@ -385,6 +453,15 @@ func (d *RESTDefinition_0_3) fillInUpdateTaskRequest(ctx context.Context, client
 	return nil
 }
 
+func (d *RESTDefinition_0_3) hydrateFromTask(t *api.Task, resourceNames map[string]string) error {
+	d.Resource = resourceNames["rest"]
+
+	if err := mapstructure.Decode(t.KindOptions, d); err != nil {
+		return errors.Wrap(err, "decoding REST options")
+	}
+	return nil
+}
+
 func (d *RESTDefinition_0_3) upgradeJST() error {
 	return nil
 }
@@ -413,6 +490,27 @@ func (d *RESTDefinition_0_3) getEnv() (api.TaskEnv, error) {
 	return nil, nil
 }
 
+// EnvDefinition_0_3 are the env vars of a task. Values are either a string,
+// or a reference to a config variable.
+type EnvDefinition_0_3 api.TaskEnv
+
+var _ json.Marshaler = EnvDefinition_0_3{}
+
+func (e EnvDefinition_0_3) MarshalJSON() ([]byte, error) {
+	env := make(map[string]interface{}, len(e))
+	for k, v := range e {
+		switch {
+		case v.Config != nil:
+			env[k] = map[string]string{"config": *v.Config}
+		case v.Value != nil:
+			env[k] = *v.Value
+		default:
+			env[k] = ""
+		}
+	}
+	return json.Marshal(env)
+}
+
 type ParameterDefinition_0_3 struct {
 	Name        string      `json:"name"`
 	Slug        string      `json:"slug"`
@@ -422,6 +520,7 @@ type ParameterDefinition_0_3 struct {
 	// TODO: default to true
 	Required bool                   `json:"required,omitempty"`
 	Options  []OptionDefinition_0_3 `json:"options,omitempty"`
+	Regex    string                 `json:"regex,omitempty"`
 }
 
 type OptionDefinition_0_3 struct {
@@ -512,6 +611,158 @@ func NewDefinition_0_3(name string, slug string, kind build.TaskKind, entrypoint
 	return def, nil
 }
 
+// NewDefinitionFromTask_0_3 converts a task into a definition.
+//
+// Resources are referenced by name and permissions are listed by role, with
+// users and groups referenced as user:<id> and group:<id>.
+func NewDefinitionFromTask_0_3(ctx context.Context, client *api.Client, task api.Task) (Definition_0_3, error) {
+	var resourceNames map[string]string
+	if task.Kind == build.TaskKindSQL || task.Kind == build.TaskKindREST {
+		resourcesByID, err := getResourcesByID(ctx, client)
+		if err != nil {
+			return Definition_0_3{}, err
+		}
+		resourceNames = map[string]string{}
+		for ref, id := range task.Resources {
+			res, ok := resourcesByID[id]
+			if !ok {
+				return Definition_0_3{}, errors.Errorf("unknown resource: %s", id)
+			}
+			resourceNames[ref] = res.Name
+		}
+	}
+	return newDefinitionFromTask_0_3(task, resourceNames)
+}
+
+// newDefinitionFromTask_0_3 converts a task into a definition, given the
+// names of its resources by ref.
+func newDefinitionFromTask_0_3(task api.Task, resourceNames map[string]string) (Definition_0_3, error) {
+	def, err := NewDefinition_0_3(task.Name, task.Slug, task.Kind, "")
+	if err != nil {
+		return Definition_0_3{}, err
+	}
+	def.Description = task.Description
+	def.Timeout = task.Timeout
+	if len(task.Constraints.Labels) > 0 {
+		constraints := task.Constraints
+		def.Constraints = &constraints
+	}
+
+	for _, p := range task.Parameters {
+		pd, err := newParameterDefinition_0_3(p)
+		if err != nil {
+			return Definition_0_3{}, err
+		}
+		def.Parameters = append(def.Parameters, pd)
+	}
+
+	if task.RequireExplicitPermissions {
+		def.Permissions = newPermissionDefinition_0_3(task.Permissions)
+	}
+
+	taskKind, err := def.taskKind()
+	if err != nil {
+		return Definition_0_3{}, err
+	}
+	if err := taskKind.hydrateFromTask(&task, resourceNames); err != nil {
+		return Definition_0_3{}, err
+	}
+
+	return def, nil
+}
+
+// newParameterDefinition_0_3 converts an API parameter into a parameter definition.
+func newParameterDefinition_0_3(p api.Parameter) (ParameterDefinition_0_3, error) {
+	pd := ParameterDefinition_0_3{
+		Name:        p.Name,
+		Slug:        p.Slug,
+		Description: p.Desc,
+		Default:     p.Default,
+		Required:    !p.Constraints.Optional,
+		Regex:       p.Constraints.Regex,
+	}
+
+	switch p.Type {
+	case api.TypeString:
+		switch p.Component {
+		case api.ComponentTextarea:
+			pd.Type = "longtext"
+		case api.ComponentEditorSQL:
+			pd.Type = "sql"
+		default:
+			pd.Type = "shorttext"
+		}
+	case api.TypeBoolean, api.TypeUpload, api.TypeInteger, api.TypeFloat, api.TypeDate, api.TypeDatetime, api.TypeConfigVar:
+		pd.Type = string(p.Type)
+	default:
+		return ParameterDefinition_0_3{}, errors.Errorf("unknown parameter type: %s", p.Type)
+	}
+
+	for _, o := range p.Constraints.Options {
+		value, ok := o.Value.(string)
+		if !ok {
+			value = fmt.Sprintf("%v", o.Value)
+		}
+		pd.Options = append(pd.Options, OptionDefinition_0_3{
+			Label: o.Label,
+			Value: value,
+		})
+	}
+
+	return pd, nil
+}
+
+// permissionRoles_0_3 lists the roles of a definition, from least to most privileged,
+// along with the action that each role grants.
+var permissionRoles_0_3 = []struct {
+	action api.Action
+	role   func(*PermissionDefinition_0_3) *[]string
+}{
+	{api.ActionGetTask, func(d *PermissionDefinition_0_3) *[]string { return &d.Viewers }},
+	{api.ActionRequestRun, func(d *PermissionDefinition_0_3) *[]string { return &d.Requesters }},
+	{api.ActionExecuteTask, func(d *PermissionDefinition_0_3) *[]string { return &d.Executers }},
+	{api.ActionUpdateTask, func(d *PermissionDefinition_0_3) *[]string { return &d.Admins }},
+}
+
+// newPermissionDefinition_0_3 groups task permissions by role.
+//
+// Each user or group is listed under the most privileged role that it was
+// granted. Permissions for other actions are not represented in a definition.
+func newPermissionDefinition_0_3(permissions api.Permissions) *PermissionDefinition_0_3 {
+	var subjects []string
+	ranks := map[string]int{}
+	for _, p := range permissions {
+		var subject string
+		if p.SubUserID != nil {
+			subject = "user:" + *p.SubUserID
+		} else if p.SubGroupID != nil {
+			subject = "group:" + *p.SubGroupID
+		} else {
+			continue
+		}
+
+		for rank, r := range permissionRoles_0_3 {
+			if r.action != p.Action {
+				continue
+			}
+			prev, ok := ranks[subject]
+			if !ok {
+				subjects = append(subjects, subject)
+			}
+			if !ok || rank > prev {
+				ranks[subject] = rank
+			}
+		}
+	}
+
+	var pd PermissionDefinition_0_3
+	for _, subject := range subjects {
+		role := permissionRoles_0_3[ranks[subject]].role(&pd)
+		*role = append(*role, subject)
+	}
+	return &pd
+}
+
 func (d Definition_0_3) Marshal(format TaskDefFormat) ([]byte, error) {
 	buf, err := json.MarshalIndent(d, "", "\t")
 	if err != nil {
@@ -645,7 +896,17 @@ func (d Definition_0_3) GetUpdateTaskRequest(ctx context.Context, client *api.Cl
 }
 
 func (d Definition_0_3) addParametersToUpdateTaskRequest(ctx context.Context, client *api.Client, req *api.UpdateTaskRequest) error {
-	req.Parameters = make([]api.Parameter, len(d.Parameters))
+	parameters, err := d.GetParameters()
+	if err != nil {
+		return err
+	}
+	req.Parameters = parameters
+	return nil
+}
+
+// GetParameters converts the definition's parameters into API parameters.
+func (d Definition_0_3) GetParameters() (api.Parameters, error) {
+	parameters := make(api.Parameters, len(d.Parameters))
 	for i, pd := range d.Parameters {
 		param := api.Parameter{
 			Name:    pd.Name,
@@ -666,13 +927,15 @@ func (d Definition_0_3) addParametersToUpdateTaskRequest(ctx context.Context, cl
 		case "boolean", "upload", "integer", "float", "date", "datetime", "configvar":
 			param.Type = api.Type(pd.Type)
 		default:
-			return errors.Errorf("unknown parameter type: %s", pd.Type)
+			return nil, errors.Errorf("unknown parameter type: %s", pd.Type)
 		}
 
 		if !pd.Required {
 			param.Constraints.Optional = true
 		}
 
+		param.Constraints.Regex = pd.Regex
+
 		if len(pd.Options) > 0 {
 			param.Constraints.Options = make([]api.ConstraintOption, len(pd.Options))
 			for j, od := range pd.Options {
@@ -681,9 +944,9 @@ func (d Definition_0_3) addParametersToUpdateTaskRequest(ctx context.Context, cl
 			}
 		}
 
-		req.Parameters[i] = param
+		parameters[i] = param
 	}
-	return nil
+	return parameters, nil
 }
 
 func (d Definition_0_3) addPermissionsToUpdateTaskRequest(ctx context.Context, client *api.Client, req *api.UpdateTaskRequest) error {
@@ -791,6 +1054,24 @@ func (d *Definition_0_3) GetSlug() string {
 	return d.Slug
 }
 
+func getResourcesByID(ctx context.Context, client *api.Client) (map[string]api.Resource, error) {
+	resp, err := client.ListResources(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching resources")
+	}
+	resourcesByID := map[string]api.Resource{}
+	for _, resource := range resp.Resources {
+		resourcesByID[resource.ID] = resource
+	}
+	return resourcesByID, nil
+}
+
+// kindOption returns the string kind option of a task with the given key.
+func kindOption(t *api.Task, key string) string {
+	v, _ := t.KindOptions[key].(string)
+	return v
+}
+
 func getResourcesByName(ctx context.Context, client *api.Client) (map[string]api.Resource, error) {
 	// Remap resources from ref -> name to ref -> id.
 	resp, err := client.ListResources(ctx)
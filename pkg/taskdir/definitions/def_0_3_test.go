@@ -1,8 +1,11 @@
 package definitions
 
 import (
+	"context"
 	"testing"
 
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/lib/pkg/build"
 	"github.com/stretchr/testify/require"
 )
 
@@ -100,5 +103,108 @@ func TestDefinition_0_3(t *testing.T) {
 		assert.Equal(fullDef, d)
 	})
 
+	t.Run("from task", func(t *testing.T) {
+		assert := require.New(t)
+		userID, groupID := "usr1", "grp1"
+		value, config := "1", "api_key"
+		task := api.Task{
+			Name:        "Hello World",
+			Slug:        "hello_world",
+			Description: "A starter task.",
+			Kind:        build.TaskKindPython,
+			KindOptions: build.KindOptions{"entrypoint": "hello_world.py"},
+			Arguments:   []string{"{{JSON.stringify(params)}}"},
+			Env: api.TaskEnv{
+				"DEBUG":   {Value: &value},
+				"API_KEY": {Config: &config},
+			},
+			Parameters: api.Parameters{
+				{
+					Name:    "Name",
+					Slug:    "name",
+					Type:    api.TypeString,
+					Desc:    "Someone's name.",
+					Default: "World",
+				},
+				{
+					Name:      "Query",
+					Slug:      "query",
+					Type:      api.TypeString,
+					Component: api.ComponentEditorSQL,
+					Constraints: api.Constraints{
+						Optional: true,
+						Regex:    "^SELECT",
+						Options:  []api.ConstraintOption{{Label: "All", Value: "SELECT *"}},
+					},
+				},
+			},
+			RequireExplicitPermissions: true,
+			Permissions: api.Permissions{
+				{Action: api.ActionGetTask, SubUserID: &userID},
+				{Action: api.ActionExecuteTask, SubUserID: &userID},
+				{Action: api.ActionGetTask, SubGroupID: &groupID},
+			},
+			Timeout: 3600,
+		}
+
+		d, err := NewDefinitionFromTask_0_3(context.Background(), nil, task)
+		assert.NoError(err)
+
+		expected := fullDef
+		expected.Parameters = append(expected.Parameters, ParameterDefinition_0_3{
+			Name:    "Query",
+			Slug:    "query",
+			Type:    "sql",
+			Regex:   "^SELECT",
+			Options: []OptionDefinition_0_3{{Label: "All", Value: "SELECT *"}},
+		})
+		expected.Permissions = &PermissionDefinition_0_3{
+			Viewers:   []string{"group:grp1"},
+			Executers: []string{"user:usr1"},
+		}
+		python := *expected.Python
+		python.Env = EnvDefinition_0_3(task.Env)
+		expected.Python = &python
+		assert.Equal(expected, d)
+
+		buf, err := d.Marshal(TaskDefFormatYAML)
+		assert.NoError(err)
+		assert.Contains(string(buf), "  env:\n    API_KEY:\n      config: api_key\n    DEBUG: \"1\"\n")
+		var exported Definition_0_3
+		assert.NoError(exported.Unmarshal(TaskDefFormatYAML, buf))
+		assert.Equal(d, exported)
+	})
+
+	t.Run("image from task", func(t *testing.T) {
+		assert := require.New(t)
+		image, value := "alpine:3", "1"
+		task := api.Task{
+			Name:    "Hello Image",
+			Slug:    "hello_image",
+			Kind:    build.TaskKindImage,
+			Image:   &image,
+			Command: []string{"echo", "hello"},
+			Env:     api.TaskEnv{"DEBUG": {Value: &value}},
+		}
+
+		d, err := NewDefinitionFromTask_0_3(context.Background(), nil, task)
+		assert.NoError(err)
+		assert.Equal(Definition_0_3{
+			Name: "Hello Image",
+			Slug: "hello_image",
+			Image: &ImageDefinition_0_3{
+				Image:   "alpine:3",
+				Command: []string{"echo", "hello"},
+				Env:     EnvDefinition_0_3(task.Env),
+			},
+		}, d)
+
+		buf, err := d.Marshal(TaskDefFormatYAML)
+		assert.NoError(err)
+		var exported Definition_0_3
+		assert.NoError(exported.Unmarshal(TaskDefFormatYAML, buf))
+		assert.Equal(d, exported)
+	})
+
 	// TODO: add tests for non-zero defaults.
 }
@@ -0,0 +1,58 @@
+package definitions
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// IgnoredDirectories are the directories that are not searched for task
+// definitions and scripts.
+var IgnoredDirectories = map[string]bool{
+	"node_modules": true,
+	"__pycache__":  true,
+	".git":         true,
+}
+
+// DiscoverTaskDefs recursively discovers the task definition files in paths,
+// skipping IgnoredDirectories.
+func DiscoverTaskDefs(paths ...string) ([]string, error) {
+	var defs []string
+	for _, p := range paths {
+		if IsTaskDef(p) {
+			// Definitions can be remote, e.g. on GitHub, so they are not stat'd.
+			defs = append(defs, p)
+			continue
+		}
+
+		fileInfo, err := os.Stat(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "determining if %s is file or directory", p)
+		}
+		if !fileInfo.IsDir() {
+			continue
+		}
+
+		err = filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if IgnoredDirectories[info.Name()] {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if IsTaskDef(path) {
+				defs = append(defs, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading directory %s", p)
+		}
+	}
+
+	return defs, nil
+}
@@ -0,0 +1,275 @@
+package definitions
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/lib/pkg/build"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Migrate_0_3 converts a definition in an older format, such as an airplane.yml
+// file, into the 0.3 format.
+//
+// Resources are already referenced by name in older definitions, so nothing is
+// looked up. Fields that have no equivalent in the 0.3 format are dropped with a warning.
+func (def Definition) Migrate_0_3() (Definition_0_3, error) {
+	kind, options, err := def.GetKindAndOptions()
+	if err != nil {
+		return Definition_0_3{}, err
+	}
+
+	task := api.Task{
+		Slug:        def.Slug,
+		Name:        def.Name,
+		Description: def.Description,
+		Arguments:   def.Arguments,
+		Parameters:  def.Parameters,
+		Constraints: def.Constraints,
+		Env:         def.Env,
+		Kind:        kind,
+		KindOptions: options,
+		Timeout:     def.Timeout,
+	}
+	if def.Image != nil {
+		task.Image = &def.Image.Image
+		// Image tasks only have a command in the 0.3 format, which the arguments are appended to.
+		task.Command = append(append([]string{}, def.Image.Command...), upgradeArguments(def.Arguments)...)
+	}
+
+	d, err := newDefinitionFromTask_0_3(task, def.Resources)
+	if err != nil {
+		return Definition_0_3{}, err
+	}
+	taskKind, err := d.taskKind()
+	if err != nil {
+		return Definition_0_3{}, err
+	}
+	if err := taskKind.upgradeJST(); err != nil {
+		return Definition_0_3{}, err
+	}
+
+	if def.Root != "" {
+		switch {
+		case d.Deno != nil:
+			d.Deno.Root = def.Root
+		case d.Dockerfile != nil:
+			d.Dockerfile.Root = def.Root
+		case d.Go != nil:
+			d.Go.Root = def.Root
+		case d.Node != nil:
+			d.Node.Root = def.Root
+		case d.Python != nil:
+			d.Python.Root = def.Root
+		case d.Shell != nil:
+			d.Shell.Root = def.Root
+		default:
+			logger.Warning("Dropping root: %s tasks do not have a root", kind)
+		}
+	}
+
+	if len(def.ResourceRequests) > 0 {
+		logger.Warning("Dropping resourceRequests: they are not supported by task definitions")
+	}
+	if def.Repo != "" {
+		logger.Warning("Dropping repo: it is set automatically when deploying from a repository")
+	}
+	if def.Node != nil && def.Node.Workdir != "" {
+		logger.Warning("Dropping node.workdir: set node.root to the directory that contains package.json instead")
+	}
+	if kind == build.TaskKindSQL && len(def.Env) > 0 {
+		logger.Warning("Dropping env: SQL tasks do not have env vars")
+	}
+
+	return d, nil
+}
+
+// MarshalMigrated_0_3 marshals def, which was migrated from the definition in
+// buf, as YAML.
+//
+// Comments of buf are carried over to the fields that they were attached to,
+// where those fields still exist after migrating. A comment above the first
+// field is about the whole file, and stays at the top.
+func MarshalMigrated_0_3(buf []byte, def Definition_0_3) ([]byte, error) {
+	out, err := def.Marshal(TaskDefFormatYAML)
+	if err != nil {
+		return nil, err
+	}
+
+	kind, err := def.Kind()
+	if err != nil {
+		return nil, err
+	}
+
+	var oldDoc yaml.Node
+	if err := yaml.Unmarshal(buf, &oldDoc); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling task definition")
+	}
+	var head string
+	if k := firstKey(&oldDoc); k != nil {
+		head, k.HeadComment = k.HeadComment, ""
+	}
+	comments := map[string]nodeComments{}
+	collectComments(&oldDoc, nil, func(p []string, c nodeComments) {
+		if np := migratedPath(p, string(kind)); np != nil {
+			comments[strings.Join(np, ".")] = c
+		}
+	})
+	if head == "" && oldDoc.HeadComment == "" && oldDoc.FootComment == "" && len(comments) == 0 {
+		return append([]byte(SchemaHeader_0_3), out...), nil
+	}
+
+	var newDoc yaml.Node
+	if err := yaml.Unmarshal(out, &newDoc); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling migrated task definition")
+	}
+	newDoc.HeadComment, newDoc.FootComment = oldDoc.HeadComment, oldDoc.FootComment
+	applyComments(&newDoc, nil, comments)
+	if k := firstKey(&newDoc); k != nil && head != "" {
+		k.HeadComment = strings.TrimSpace(head + "\n" + k.HeadComment)
+	}
+
+	b := bytes.NewBufferString(SchemaHeader_0_3)
+	enc := yaml.NewEncoder(b)
+	enc.SetIndent(2)
+	if err := enc.Encode(&newDoc); err != nil {
+		return nil, errors.Wrap(err, "marshalling task definition")
+	}
+	return b.Bytes(), nil
+}
+
+// firstKey returns the key of the first field of doc, or nil if it has none.
+func firstKey(doc *yaml.Node) *yaml.Node {
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode || len(doc.Content[0].Content) == 0 {
+		return nil
+	}
+	return doc.Content[0].Content[0]
+}
+
+// nodeComments are the comments of a mapping entry or a sequence item.
+type nodeComments struct {
+	key, value [3]string
+}
+
+func getComments(n *yaml.Node) [3]string {
+	if n == nil {
+		return [3]string{}
+	}
+	return [3]string{n.HeadComment, n.LineComment, n.FootComment}
+}
+
+func setComments(n *yaml.Node, c [3]string) {
+	if n == nil {
+		return
+	}
+	n.HeadComment, n.LineComment, n.FootComment = c[0], c[1], c[2]
+}
+
+// collectComments calls fn with the path and comments of every commented
+// mapping entry and sequence item under n.
+func collectComments(n *yaml.Node, p []string, fn func([]string, nodeComments)) {
+	walkYAML(n, p, func(p []string, key, value *yaml.Node) {
+		c := nodeComments{key: getComments(key), value: getComments(value)}
+		if c != (nodeComments{}) {
+			fn(p, c)
+		}
+	})
+}
+
+// applyComments sets the comments of the mapping entries and sequence items
+// under n, by path.
+func applyComments(n *yaml.Node, p []string, comments map[string]nodeComments) {
+	walkYAML(n, p, func(p []string, key, value *yaml.Node) {
+		if c, ok := comments[strings.Join(p, ".")]; ok {
+			if key != nil && value.Kind != yaml.ScalarNode && c.value[1] != "" {
+				// Line comments of scalar values would end up below the key of other values.
+				c.key[1] = strings.TrimSpace(c.key[1] + " " + c.value[1])
+				c.value[1] = ""
+			}
+			setComments(key, c.key)
+			setComments(value, c.value)
+		}
+	})
+}
+
+// walkYAML calls fn with the path of every mapping entry and sequence item
+// under n. The key is nil for sequence items.
+func walkYAML(n *yaml.Node, p []string, fn func(p []string, key, value *yaml.Node)) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			walkYAML(c, p, fn)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			cp := append(append([]string{}, p...), n.Content[i].Value)
+			fn(cp, n.Content[i], n.Content[i+1])
+			walkYAML(n.Content[i+1], cp, fn)
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			cp := append(append([]string{}, p...), strconv.Itoa(i))
+			fn(cp, nil, c)
+			walkYAML(c, cp, fn)
+		}
+	}
+}
+
+// migratedPath returns the path that a field of an older definition has in
+// the 0.3 format, or nil if it was dropped.
+func migratedPath(p []string, kind string) []string {
+	if len(p) == 0 {
+		return p
+	}
+	under := func(prefix ...string) []string {
+		return append(prefix, p[1:]...)
+	}
+	switch p[0] {
+	case "arguments":
+		if kind == string(build.TaskKindImage) {
+			return nil
+		}
+		return under(kind, "arguments")
+	case "env", "root":
+		return under(kind, p[0])
+	case "resources":
+		return []string{kind, "resource"}
+	case "resourceRequests", "repo":
+		return nil
+	case "parameters":
+		if len(p) < 3 {
+			return p
+		}
+		switch p[2] {
+		case "desc":
+			return append([]string{"parameters", p[1], "description"}, p[3:]...)
+		case "component":
+			return nil
+		case "constraints":
+			if len(p) < 4 {
+				return nil
+			}
+			if p[3] == "optional" {
+				return []string{"parameters", p[1], "required"}
+			}
+			return append([]string{"parameters", p[1]}, p[3:]...)
+		}
+	case kind:
+		if kind == string(build.TaskKindSQL) && len(p) > 1 && p[1] == "query" {
+			return []string{kind, "entrypoint"}
+		}
+		if kind == string(build.TaskKindREST) && len(p) > 1 {
+			switch p[1] {
+			case "jsonBody":
+				return []string{kind, "body"}
+			case "formUrlEncodedBody", "formDataBody":
+				return append([]string{kind, "formData"}, p[2:]...)
+			}
+		}
+	}
+	return p
+}
@@ -0,0 +1,136 @@
+package definitions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate_0_3(t *testing.T) {
+	assert := require.New(t)
+
+	buf := []byte(`# Greets people.
+slug: hello
+name: Hello # display name
+root: ./src
+arguments:
+  - "{{JSON}}" # all params
+env:
+  # the API key
+  API_KEY:
+    config: api_key
+  DEBUG:
+    value: "1"
+parameters:
+  # who to greet
+  - name: Name
+    slug: name
+    type: string
+    desc: Who
+    constraints:
+      optional: true
+      regex: "^[a-z]+$"
+  - name: Count
+    slug: count
+    type: integer
+node:
+  entrypoint: main.ts # compiled with esbuild
+  language: typescript
+  nodeVersion: "16"
+`)
+	def, err := UnmarshalDefinition(buf, "airplane.yml")
+	assert.NoError(err)
+
+	d, err := def.Migrate_0_3()
+	assert.NoError(err)
+	assert.Equal("hello", d.Slug)
+	assert.Equal([]ParameterDefinition_0_3{
+		{Name: "Name", Slug: "name", Type: "shorttext", Description: "Who", Regex: "^[a-z]+$"},
+		{Name: "Count", Slug: "count", Type: "integer", Required: true},
+	}, d.Parameters)
+	assert.Equal("./src", d.Node.Root)
+	assert.Equal([]string{"{{JSON.stringify(params)}}"}, d.Node.Arguments)
+
+	out, err := MarshalMigrated_0_3(buf, d)
+	assert.NoError(err)
+	assert.Equal(SchemaHeader_0_3+`# Greets people.
+name: Hello # display name
+slug: hello
+parameters:
+  # who to greet
+  - name: Name
+    slug: name
+    type: shorttext
+    description: Who
+    regex: ^[a-z]+$
+  - name: Count
+    slug: count
+    type: integer
+    required: true
+node:
+  entrypoint: main.ts # compiled with esbuild
+  nodeVersion: "16"
+  arguments:
+    - "{{JSON.stringify(params)}}" # all params
+  root: ./src
+  env:
+    # the API key
+    API_KEY:
+      config: api_key
+    DEBUG: "1"
+`, string(out))
+
+	var migrated Definition_0_3
+	assert.NoError(migrated.Unmarshal(TaskDefFormatYAML, out))
+	assert.Equal(d, migrated)
+}
+
+func TestMigrate_0_3Resources(t *testing.T) {
+	t.Run("sql", func(t *testing.T) {
+		assert := require.New(t)
+
+		buf := []byte(`slug: report
+name: Report
+resources:
+  db: Reporting DB # read replica
+sql:
+  query: SELECT 1
+`)
+		def, err := UnmarshalDefinition(buf, "airplane.yml")
+		assert.NoError(err)
+
+		d, err := def.Migrate_0_3()
+		assert.NoError(err)
+		assert.Equal(&SQLDefinition_0_3{Resource: "Reporting DB", Entrypoint: "report.sql"}, d.SQL)
+
+		out, err := MarshalMigrated_0_3(buf, d)
+		assert.NoError(err)
+		assert.Equal(SchemaHeader_0_3+`name: Report
+slug: report
+sql:
+  resource: Reporting DB # read replica
+  entrypoint: report.sql
+`, string(out))
+	})
+
+	t.Run("rest", func(t *testing.T) {
+		assert := require.New(t)
+
+		buf := []byte(`slug: ping
+name: Ping
+resources:
+  rest: Status API
+rest:
+  method: GET
+  path: /ping
+`)
+		def, err := UnmarshalDefinition(buf, "airplane.yml")
+		assert.NoError(err)
+
+		d, err := def.Migrate_0_3()
+		assert.NoError(err)
+		assert.Equal("Status API", d.REST.Resource)
+		assert.Equal("GET", d.REST.Method)
+		assert.Equal("/ping", d.REST.Path)
+	})
+}
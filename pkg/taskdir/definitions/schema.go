@@ -0,0 +1,43 @@
+package definitions
+
+import (
+	"encoding/json"
+
+	"github.com/alecthomas/jsonschema"
+	"github.com/pkg/errors"
+)
+
+// SchemaVersions are the versions of task definitions that have a schema.
+var SchemaVersions = []string{"0.1", "0.2", "0.3"}
+
+// SchemaURL_0_3 is where the schema of 0.3 task definitions is published.
+const SchemaURL_0_3 = "https://raw.githubusercontent.com/airplanedev/cli/main/pkg/taskdir/definitions/schema_0_3.json"
+
+// SchemaHeader_0_3 is a comment that YAML task definitions start with, so
+// that editors using the YAML language server validate and complete them.
+const SchemaHeader_0_3 = "# yaml-language-server: $schema=" + SchemaURL_0_3 + "\n"
+
+// Schema returns the JSON schema of task definitions of the given version.
+//
+// The schema of 0.3 definitions (*.task.yaml files) is the one they are
+// validated with, older ones (airplane.yml files) are reflected from their types.
+func Schema(version string) ([]byte, error) {
+	var def interface{}
+	switch version {
+	case "0.3":
+		return []byte(schemaStr), nil
+	case "0.2":
+		def = Definition{}
+	case "0.1":
+		def = Definition_0_1{}
+	default:
+		return nil, errors.Errorf("unknown task definition version %q, expected one of %v", version, SchemaVersions)
+	}
+
+	r := &jsonschema.Reflector{PreferYAMLSchema: true}
+	buf, err := json.MarshalIndent(r.Reflect(def), "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal schema")
+	}
+	return append(buf, '\n'), nil
+}
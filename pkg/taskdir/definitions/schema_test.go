@@ -0,0 +1,21 @@
+package definitions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	for _, version := range SchemaVersions {
+		t.Run(version, func(t *testing.T) {
+			buf, err := Schema(version)
+			require.NoError(t, err)
+			require.True(t, json.Valid(buf))
+		})
+	}
+
+	_, err := Schema("0.4")
+	require.Error(t, err)
+}
@@ -0,0 +1,386 @@
+package definitions
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/configs"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+	"github.com/pkg/errors"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ValidationError is a problem found in a task definition.
+type ValidationError struct {
+	// Path is the path of the invalid value, such as $.parameters[0].slug.
+	Path string
+	// Line and Column are the position of the invalid value,
+	// or of its closest parent that exists. They are 0 if unknown.
+	Line   int
+	Column int
+	// Message describes the problem.
+	Message string
+}
+
+func (err ValidationError) Error() string {
+	if err.Line == 0 {
+		return err.Message
+	}
+	return fmt.Sprintf("%d:%d: %s", err.Line, err.Column, err.Message)
+}
+
+// fieldPath is the path of a value in a definition, made of keys and indices.
+type fieldPath []interface{}
+
+func (p fieldPath) child(elems ...interface{}) fieldPath {
+	return append(append(fieldPath{}, p...), elems...)
+}
+
+func (p fieldPath) yamlPath() *yaml.Path {
+	b := (&yaml.PathBuilder{}).Root()
+	for _, elem := range p {
+		switch e := elem.(type) {
+		case int:
+			b = b.Index(uint(e))
+		default:
+			b = b.Child(fmt.Sprint(e))
+		}
+	}
+	return b.Build()
+}
+
+func (p fieldPath) String() string {
+	return p.yamlPath().String()
+}
+
+// validator collects the validation errors of a definition.
+type validator struct {
+	file   *ast.File
+	errors []ValidationError
+}
+
+func (v *validator) errorf(p fieldPath, format string, args ...interface{}) {
+	err := ValidationError{
+		Path:    p.String(),
+		Message: fmt.Sprintf(format, args...),
+	}
+	// Values that are missing have no position, use the closest parent instead.
+	for ; v.file != nil; p = p[:len(p)-1] {
+		var node ast.Node
+		if len(p) == 0 {
+			if len(v.file.Docs) > 0 {
+				node = v.file.Docs[0].Body
+			}
+		} else if n, ferr := p.yamlPath().FilterFile(v.file); ferr == nil {
+			node = n
+		}
+		if tok := nodeToken(node); tok != nil {
+			err.Line = tok.Position.Line
+			err.Column = tok.Position.Column
+			break
+		}
+		if len(p) == 0 {
+			break
+		}
+	}
+	v.errors = append(v.errors, err)
+}
+
+// nodeToken returns the token that a node starts at.
+func nodeToken(node ast.Node) *token.Token {
+	// The token of a mapping is the ':' of its first value, use its key instead.
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case *ast.MappingNode:
+		if len(n.Values) > 0 {
+			return n.Values[0].Key.GetToken()
+		}
+	case *ast.MappingValueNode:
+		return n.Key.GetToken()
+	}
+	return node.GetToken()
+}
+
+// Validate_0_3 validates the task definition in buf, read from defPath, and
+// returns all of the problems found in it, sorted by position.
+//
+// On top of validating the definition against its schema, it checks that its
+// root and entrypoint exist, that parameters are consistent and that config
+// variables are referenced by valid names.
+func Validate_0_3(buf []byte, defPath string) ([]ValidationError, error) {
+	v := &validator{}
+
+	// JSON is valid YAML, so both formats are parsed as YAML to find positions.
+	file, err := parser.ParseBytes(buf, 0)
+	if err != nil {
+		return []ValidationError{{Path: "$", Message: yaml.FormatError(err, false, false)}}, nil
+	}
+	v.file = file
+
+	if GetTaskDefFormat(defPath) == TaskDefFormatYAML {
+		if buf, err = yaml.YAMLToJSON(buf); err != nil {
+			return nil, errors.Wrap(err, "converting to json")
+		}
+	}
+	result, err := gojsonschema.Validate(gojsonschema.NewStringLoader(schemaStr), gojsonschema.NewBytesLoader(buf))
+	if err != nil {
+		return nil, errors.Wrap(err, "validating schema")
+	}
+	if !result.Valid() {
+		for _, rerr := range schemaErrors(result.Errors()) {
+			v.errorf(schemaFieldPath(rerr), "%s", schemaMessage(rerr))
+		}
+		return v.sorted(), nil
+	}
+
+	var def Definition_0_3
+	if err := json.Unmarshal(buf, &def); err != nil {
+		return nil, errors.Wrap(err, "unmarshal definition")
+	}
+	v.validateKind(def, defPath)
+	v.validateParameters(def.Parameters)
+
+	return v.sorted(), nil
+}
+
+func (v *validator) sorted() []ValidationError {
+	sort.SliceStable(v.errors, func(i, j int) bool {
+		if v.errors[i].Line != v.errors[j].Line {
+			return v.errors[i].Line < v.errors[j].Line
+		}
+		return v.errors[i].Column < v.errors[j].Column
+	})
+	return v.errors
+}
+
+// schemaErrors returns the errors that explain why a definition does not
+// match the schema, leaving out errors about combined schemas such as "Must
+// validate one and only one schema (oneOf)" if there are more specific ones.
+func schemaErrors(errs []gojsonschema.ResultError) []gojsonschema.ResultError {
+	var specific []gojsonschema.ResultError
+	for _, rerr := range errs {
+		switch rerr.Type() {
+		case "number_one_of", "number_all_of", "number_any_of":
+		default:
+			specific = append(specific, rerr)
+		}
+	}
+	if len(specific) == 0 {
+		return errs
+	}
+	return specific
+}
+
+// kinds_0_3 are the keys of the kinds of tasks in definitions.
+var kinds_0_3 = []string{"deno", "dockerfile", "go", "image", "node", "python", "shell", "sql", "rest"}
+
+// schemaMessage returns a message for a schema error.
+func schemaMessage(rerr gojsonschema.ResultError) string {
+	// When the kind is missing, the schema reports the first kind that it tried.
+	if rerr.Type() == "required" && rerr.Field() == "(root)" {
+		property, _ := rerr.Details()["property"].(string)
+		for _, kind := range kinds_0_3 {
+			if property == kind {
+				return fmt.Sprintf("the kind of task is missing, expected one of: %s", strings.Join(kinds_0_3, ", "))
+			}
+		}
+	}
+	return rerr.Description()
+}
+
+// schemaFieldPath returns the path of the value that a schema error is about.
+func schemaFieldPath(rerr gojsonschema.ResultError) fieldPath {
+	var p fieldPath
+	// Fields look like (root).parameters.0.slug
+	for _, elem := range strings.Split(rerr.Field(), ".") {
+		if elem == "(root)" || elem == "" {
+			continue
+		}
+		if i, err := strconv.Atoi(elem); err == nil {
+			p = append(p, i)
+		} else {
+			p = append(p, elem)
+		}
+	}
+	return p
+}
+
+func (v *validator) validateKind(def Definition_0_3, defPath string) {
+	kind, err := def.Kind()
+	if err != nil {
+		v.errorf(nil, "%s", err)
+		return
+	}
+	taskKind, err := def.taskKind()
+	if err != nil {
+		v.errorf(nil, "%s", err)
+		return
+	}
+	kindPath := fieldPath{string(kind)}
+
+	dir := filepath.Dir(defPath)
+	r, err := taskKind.getRoot()
+	if err != nil {
+		v.errorf(kindPath, "%s", err)
+		return
+	}
+	root := filepath.Join(dir, r)
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		v.errorf(kindPath.child("root"), "root directory %s does not exist", root)
+	} else if absRoot, absDef := abs(root), abs(defPath); !strings.HasPrefix(absDef, absRoot+string(filepath.Separator)) {
+		v.errorf(kindPath.child("root"), "%s must be inside of the task's root directory: %s", filepath.Base(defPath), root)
+	} else if ep, err := taskKind.getEntrypoint(); err != nil && err != ErrNoEntrypoint {
+		v.errorf(kindPath, "%s", err)
+	} else if err == nil && ep != "" {
+		if info, err := os.Stat(filepath.Join(root, ep)); err != nil || info.IsDir() {
+			v.errorf(kindPath.child("entrypoint"), "entrypoint %s does not exist in %s", ep, root)
+		}
+	}
+
+	env, err := taskKind.getEnv()
+	if err != nil {
+		v.errorf(kindPath, "%s", err)
+		return
+	}
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ref := env[name].Config; ref != nil {
+			if err := validateConfigName(*ref); err != nil {
+				v.errorf(kindPath.child("env", name, "config"), "%s", err)
+			}
+		}
+	}
+}
+
+func abs(path string) string {
+	if p, err := filepath.Abs(path); err == nil {
+		return p
+	}
+	return path
+}
+
+func (v *validator) validateParameters(params []ParameterDefinition_0_3) {
+	slugs := map[string]int{}
+	for i, p := range params {
+		pp := fieldPath{"parameters", i}
+
+		if j, ok := slugs[p.Slug]; ok {
+			v.errorf(pp.child("slug"), "duplicate slug %q, also used by parameters[%d]", p.Slug, j)
+		} else {
+			slugs[p.Slug] = i
+		}
+
+		var re *regexp.Regexp
+		if p.Regex != "" {
+			var err error
+			if re, err = regexp.Compile(p.Regex); err != nil {
+				v.errorf(pp.child("regex"), "invalid regex: %s", err)
+			}
+		}
+
+		for j, o := range p.Options {
+			if err := validateParameterValue(p.Type, o.Value); err != nil {
+				v.errorf(pp.child("options", j), "invalid option: %s", err)
+			}
+		}
+
+		if p.Default == nil {
+			continue
+		}
+		if err := validateParameterValue(p.Type, p.Default); err != nil {
+			v.errorf(pp.child("default"), "invalid default: %s", err)
+			continue
+		}
+		if s, ok := p.Default.(string); ok && re != nil && !re.MatchString(s) {
+			v.errorf(pp.child("default"), "default %q does not match regex %s", s, p.Regex)
+		}
+		if len(p.Options) > 0 && !hasOption(p.Options, p.Default) {
+			v.errorf(pp.child("default"), "default %v is not one of the options", p.Default)
+		}
+	}
+}
+
+// validateParameterValue checks that v is a valid value for a parameter of type typ.
+//
+// Options are strings, so values of other types may be given as strings.
+func validateParameterValue(typ string, v interface{}) error {
+	s, isString := v.(string)
+	switch typ {
+	case "shorttext", "longtext", "sql":
+		if !isString {
+			return errors.Errorf("expected a string, got %v", v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			if _, err := strconv.ParseBool(s); !isString || err != nil {
+				return errors.Errorf("expected a boolean, got %v", v)
+			}
+		}
+	case "integer":
+		f, ok := v.(float64)
+		if isString {
+			var err error
+			f, err = strconv.ParseFloat(s, 64)
+			ok = err == nil
+		}
+		if !ok || f != math.Trunc(f) {
+			return errors.Errorf("expected an integer, got %v", v)
+		}
+	case "float":
+		if _, ok := v.(float64); !ok {
+			if _, err := strconv.ParseFloat(s, 64); !isString || err != nil {
+				return errors.Errorf("expected a number, got %v", v)
+			}
+		}
+	case "date":
+		if _, err := time.Parse("2006-01-02", s); !isString || err != nil {
+			return errors.Errorf("expected a date formatted as 2006-01-02, got %v", v)
+		}
+	case "datetime":
+		if _, err := time.Parse(time.RFC3339, s); !isString || err != nil {
+			return errors.Errorf("expected a datetime formatted as 2006-01-02T15:04:05Z, got %v", v)
+		}
+	case "configvar":
+		if !isString {
+			return errors.Errorf("expected a config variable name, got %v", v)
+		}
+		return validateConfigName(s)
+	case "upload":
+		return errors.New("upload parameters cannot have a default or options")
+	}
+	return nil
+}
+
+func hasOption(options []OptionDefinition_0_3, v interface{}) bool {
+	for _, o := range options {
+		if o.Value == fmt.Sprint(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateConfigName checks that name is a valid reference to a config variable.
+func validateConfigName(name string) error {
+	nt, err := configs.ParseName(name)
+	if err != nil || nt.Name == "" || strings.ContainsAny(name, " \t\n") || strings.HasSuffix(name, ":") {
+		return errors.Errorf("invalid config name %q - expected my_config or my_config:tag", name)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package definitions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_0_3(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "task.sh"), []byte("echo hi"), 0644))
+
+	validate := func(t *testing.T, name, def string) []ValidationError {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(def), 0644))
+		verrs, err := Validate_0_3([]byte(def), path)
+		require.NoError(t, err)
+		return verrs
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		verrs := validate(t, "valid.task.yaml", `
+name: Valid
+slug: valid
+parameters:
+  - name: Count
+    slug: count
+    type: integer
+    default: 3
+    options: ["1", "3"]
+shell:
+  entrypoint: task.sh
+  env:
+    DB_URL:
+      config: db/url:prod
+`)
+		require.Empty(t, verrs)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var assert = require.New(t)
+		verrs := validate(t, "invalid.task.yaml", `name: Invalid
+slug: invalid
+parameters:
+  - name: A
+    slug: a
+    type: shorttext
+    regex: "[a-"
+  - name: B
+    slug: a
+    type: integer
+    default: 1.5
+shell:
+  entrypoint: missing.sh
+`)
+		assert.Equal([]ValidationError{
+			{Path: "$.parameters[0].regex", Line: 7, Column: 12, Message: "invalid regex: error parsing regexp: missing closing ]: `[a-`"},
+			{Path: "$.parameters[1].slug", Line: 9, Column: 11, Message: `duplicate slug "a", also used by parameters[0]`},
+			{Path: "$.parameters[1].default", Line: 11, Column: 14, Message: "invalid default: expected an integer, got 1.5"},
+			{Path: "$.shell.entrypoint", Line: 13, Column: 15, Message: "entrypoint missing.sh does not exist in " + dir},
+		}, verrs)
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		var assert = require.New(t)
+		verrs := validate(t, "schema.task.json", `{
+  "name": "Schema",
+  "slug": "schema",
+  "shell": {"entrypoint": "task.sh", "unknown": true}
+}`)
+		assert.Len(verrs, 1)
+		assert.Equal(4, verrs[0].Line)
+	})
+}
@@ -2,8 +2,13 @@ package token
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +21,11 @@ import (
 // for a token request, when a token is received the
 // server sends the token on the channel returned from `Tokens()`.
 //
+// Requests must carry the random state of the server,
+// which is the path of its URL, so that other local processes
+// cannot inject a token of their own. The login flow
+// redirects to the URL with `?token=<token>` appended.
+//
 // It is important to configure the server with a shared
 // context as it relies on it to shutdown in case a CLI
 // login attempt is canceled.
@@ -35,12 +45,18 @@ type Server struct {
 	lstn            net.Listener
 	ctx             context.Context
 	loginSuccessURL string
+	state           string
 	wg              sync.WaitGroup
 	server          *http.Server
 }
 
 // NewServer returns a new server.
 func NewServer(ctx context.Context, loginSuccessURL string) (*Server, error) {
+	state, err := NewState()
+	if err != nil {
+		return nil, err
+	}
+
 	l, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
 		return nil, errors.Wrap(err, "bind")
@@ -51,6 +67,7 @@ func NewServer(ctx context.Context, loginSuccessURL string) (*Server, error) {
 		lstn:            l,
 		ctx:             ctx,
 		loginSuccessURL: loginSuccessURL,
+		state:           state,
 	}
 	srv.server = &http.Server{
 		Handler: srv,
@@ -60,9 +77,9 @@ func NewServer(ctx context.Context, loginSuccessURL string) (*Server, error) {
 	return srv, nil
 }
 
-// URL returns the server's URL.
+// URL returns the server's URL, with its state as the path.
 func (srv *Server) URL() string {
-	return "http://" + srv.lstn.Addr().String()
+	return "http://" + srv.lstn.Addr().String() + "/" + srv.state
 }
 
 // Token returns the token channel.
@@ -72,6 +89,11 @@ func (srv *Server) Token() <-chan string {
 
 // ServeHTTP implementation.
 func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !validState(strings.TrimPrefix(r.URL.Path, "/"), srv.state) {
+		http.Error(w, "invalid state", http.StatusBadRequest)
+		return
+	}
+
 	select {
 	case <-r.Context().Done():
 	case srv.tokens <- r.URL.Query().Get("token"):
@@ -111,3 +133,45 @@ func (srv *Server) Close() error {
 
 	return nil
 }
+
+// NewState returns a random state to verify that tokens
+// come from the login flow that was started by the CLI.
+func NewState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrap(err, "generating state")
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Parse parses a token pasted by a user.
+//
+// The input is either the token itself, or the URL that the
+// login flow redirected to, in which case the state in its
+// path must match.
+func Parse(input, state string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", errors.New("no token was entered")
+	}
+	if !strings.Contains(input, "://") {
+		return input, nil
+	}
+
+	u, err := url.Parse(input)
+	if err != nil {
+		return "", errors.Wrap(err, "parse url")
+	}
+	q := u.Query()
+	if !validState(strings.TrimPrefix(u.Path, "/"), state) {
+		return "", errors.New("the url does not belong to this login attempt, please try again")
+	}
+	if q.Get("token") == "" {
+		return "", errors.New("the url does not contain a token")
+	}
+	return q.Get("token"), nil
+}
+
+func validState(got, want string) bool {
+	return want != "" && subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
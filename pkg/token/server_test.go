@@ -3,6 +3,7 @@ package token
 import (
 	"context"
 	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -21,6 +22,78 @@ func TestServer(t *testing.T) {
 		assert.Equal("token", <-srv.Token())
 		assert.NoError(srv.Close())
 	})
+
+	t.Run("reject a token without state", func(t *testing.T) {
+		var ctx = context.Background()
+		var assert = require.New(t)
+
+		srv, err := NewServer(ctx, "https://fake.airplane.so/cli/success")
+		assert.NoError(err)
+
+		u, err := url.Parse(srv.URL())
+		assert.NoError(err)
+		for _, path := range []string{"/", "/other"} {
+			u.Path = path
+			resp, err := http.Get(u.String() + "?token=token")
+			assert.NoError(err)
+			resp.Body.Close()
+			assert.Equal(http.StatusBadRequest, resp.StatusCode)
+		}
+
+		select {
+		case token := <-srv.Token():
+			t.Fatalf("unexpected token %q", token)
+		default:
+		}
+		assert.NoError(srv.Close())
+	})
+
+	t.Run("redirect to the success page", func(t *testing.T) {
+		var ctx = context.Background()
+		var assert = require.New(t)
+
+		srv, err := NewServer(ctx, "https://fake.airplane.so/cli/success")
+		assert.NoError(err)
+
+		// The login flow appends the token to the URL it was given.
+		var client = &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		resp, err := client.Get(srv.URL() + "?token=token")
+		assert.NoError(err)
+		resp.Body.Close()
+		assert.Equal(http.StatusSeeOther, resp.StatusCode)
+		assert.Equal("https://fake.airplane.so/cli/success", resp.Header.Get("Location"))
+
+		assert.Equal("token", <-srv.Token())
+		assert.NoError(srv.Close())
+	})
+}
+
+func TestParse(t *testing.T) {
+	var assert = require.New(t)
+
+	token, err := Parse(" token\n", "state")
+	assert.NoError(err)
+	assert.Equal("token", token)
+
+	token, err = Parse("http://127.0.0.1/state?token=token", "state")
+	assert.NoError(err)
+	assert.Equal("token", token)
+
+	_, err = Parse("http://127.0.0.1/other?token=token", "state")
+	assert.Error(err)
+
+	_, err = Parse("http://127.0.0.1/?token=token", "state")
+	assert.Error(err)
+
+	_, err = Parse("http://127.0.0.1/state", "state")
+	assert.Error(err)
+
+	_, err = Parse("", "state")
+	assert.Error(err)
 }
 
 func send(t testing.TB, url, token string) {
@@ -33,6 +33,13 @@ func WithParentPersistentPreRunE(f func(cmd *cobra.Command, args []string) error
 	}
 }
 
+// RootPersistentPreRunE runs only the root command's PersistentPreRunE, skipping
+// those of the parents in between, such as the login check of the tasks command.
+// It is meant for commands that work offline.
+func RootPersistentPreRunE(cmd *cobra.Command, args []string) error {
+	return cmd.Root().PersistentPreRunE(cmd, args)
+}
+
 // TimeValue is a pflag.Value that can be used to parse a time.Time
 // as a Cobra flag.
 //
@@ -40,7 +47,8 @@ func WithParentPersistentPreRunE(f func(cmd *cobra.Command, args []string) error
 //   var tv timeValue
 //   cmd.Flags().Var(&tv, "since", "Filters by created_at")
 //
-// Which could be set as: `--since="2020-01-02T01:02:03"`
+// Which could be set as: `--since="2020-01-02T01:02:03"`, or as a duration
+// before now such as `--since=10m`.
 //
 // TimeValue's are alias types of time.Time. You can convert safely via `time.Time(tv)`.
 type TimeValue time.Time
@@ -48,6 +56,12 @@ type TimeValue time.Time
 var _ pflag.Value = &TimeValue{}
 
 func (tv *TimeValue) Set(s string) error {
+	// A duration is relative to now, e.g. 10m means 10 minutes ago.
+	if d, err := time.ParseDuration(s); err == nil {
+		*tv = TimeValue(time.Now().Add(-d))
+		return nil
+	}
+
 	// Cobra doesn't appear to support quoted strings with spaces:
 	// https://github.com/spf13/cobra/issues/1114
 	// If fixed, we could start supporting time formats with spaces like "2006-01-02 15:04:05".
@@ -73,7 +87,7 @@ func (tv *TimeValue) Set(s string) error {
 	}
 
 	// If we did not find a match, return a helpful error message:
-	return errors.New(`expected timestamp formatted as "2021-04-16" or "2021-04-16T01:30:59"`)
+	return errors.New(`expected timestamp formatted as "2021-04-16" or "2021-04-16T01:30:59", or a duration such as "10m"`)
 }
 
 func (tv *TimeValue) Type() string {
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Diff returns a unified diff of the a and b texts, or an empty string
+// if they are equal.
+func Diff(a, b, fromFile, toFile string) (string, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(a),
+		B:        difflib.SplitLines(b),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+	return diff, errors.Wrap(err, "computing diff")
+}
+
+// ColorDiff colors the added and removed lines of a unified diff.
+func ColorDiff(diff string) string {
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			lines[i] = logger.Bold(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = logger.Green(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = logger.Red(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = logger.Gray(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -4,3 +4,22 @@ type ErrorExplained interface {
 	Error() string
 	ExplainError() string
 }
+
+// ExitCodeError is an error that exits the CLI with a specific status code.
+//
+// When Err is nil, the CLI exits without printing an error.
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (err ExitCodeError) Error() string {
+	if err.Err == nil {
+		return ""
+	}
+	return err.Err.Error()
+}
+
+func (err ExitCodeError) Unwrap() error {
+	return err.Err
+}
@@ -35,6 +35,20 @@ func ConfirmWithAssumptions(question string, assumeYes, assumeNo bool) (bool, er
 	return Confirm(question)
 }
 
+// Password prompts for a secret without echoing it.
+func Password(message string) (string, error) {
+	var secret string
+	if err := survey.AskOne(
+		&survey.Password{Message: message},
+		&secret,
+		survey.WithStdio(os.Stdin, os.Stderr, os.Stderr),
+	); err != nil {
+		return "", errors.Wrap(err, "prompting")
+	}
+
+	return secret, nil
+}
+
 // CanPrompt checks that both stdin and stderr are terminal
 func CanPrompt() bool {
 	return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stderr.Fd())